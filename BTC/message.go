@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// bitcoinMessageMagic prefixes messages signed in the legacy format.
+const bitcoinMessageMagic = "Bitcoin Signed Message:\n"
+
+// BIP137 header byte ranges; the low two bits carry the recovery ID.
+const (
+	bip137HeaderP2PKHUncompressed = 27
+	bip137HeaderP2PKHCompressed   = 31
+	bip137HeaderP2SHP2WPKH        = 35
+	bip137HeaderP2WPKH            = 39
+)
+
+// -------------------------------
+// ✍️ Sign Message
+// -------------------------------
+
+// signBitcoinMessage proves ownership of the address of the given script
+// type for the account's key. P2PKH uses the legacy BIP137 format,
+// P2WPKH and P2TR use BIP322 simple signatures. The result is base64.
+func signBitcoinMessage(account BitcoinAccount, message, scriptType string, isMainnet bool) string {
+	network := bitcoinNetParams(isMainnet)
+
+	key, err := btcutil.DecodeWIF(account.WIF)
+	if err != nil {
+		log.Fatalf("❌ Invalid WIF: %v", err)
+	}
+	addr, err := bitcoinAddressForPubKey(key.PrivKey.PubKey(), scriptType, network)
+	if err != nil {
+		log.Fatalf("❌ Failed to derive address: %v", err)
+	}
+
+	var signature []byte
+	switch scriptType {
+	case ScriptP2PKH:
+		signature, err = ecdsa.SignCompact(key.PrivKey, bitcoinMessageHash(message), key.CompressPubKey)
+	case ScriptP2WPKH, ScriptP2TR:
+		signature, err = signBIP322Simple(key.PrivKey, addr, message)
+	default:
+		log.Fatalf("❌ Message signing is not supported for %s addresses", scriptType)
+	}
+	if err != nil {
+		log.Fatalf("❌ Failed to sign message: %v", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(signature)
+	fmt.Println("✅ Message signed:")
+	fmt.Println("🏦 Address:", addr.EncodeAddress())
+	fmt.Println("✍️ Signature:", encoded)
+	return encoded
+}
+
+// bitcoinMessageHash is the double-SHA256 of the magic-prefixed message,
+// as used by BIP137.
+func bitcoinMessageHash(message string) []byte {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, bitcoinMessageMagic)
+	wire.WriteVarString(&buf, 0, message)
+	return chainhash.DoubleHashB(buf.Bytes())
+}
+
+// bip322MessageHash is the BIP340 tagged hash committed to by to_spend.
+func bip322MessageHash(message string) []byte {
+	return chainhash.TaggedHash([]byte("BIP0322-signed-message"), []byte(message))[:]
+}
+
+// bip322Transactions builds the virtual to_spend and to_sign transactions
+// of BIP322 for the given output script and message.
+func bip322Transactions(pkScript []byte, message string) (toSpend, toSign *wire.MsgTx, err error) {
+	scriptSig, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(bip322MessageHash(message)).
+		Script()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	toSpend = wire.NewMsgTx(0)
+	toSpend.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, 0xffffffff), scriptSig, nil))
+	toSpend.TxIn[0].Sequence = 0
+	toSpend.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	toSpendHash := toSpend.TxHash()
+	toSign = wire.NewMsgTx(0)
+	toSign.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&toSpendHash, 0), nil, nil))
+	toSign.TxIn[0].Sequence = 0
+	toSign.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	return toSpend, toSign, nil
+}
+
+func signBIP322Simple(privKey *btcec.PrivateKey, addr btcutil.Address, message string) ([]byte, error) {
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	_, toSign, err := bip322Transactions(pkScript, message)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher := txscript.NewCannedPrevOutputFetcher(pkScript, 0)
+	sigHashes := txscript.NewTxSigHashes(toSign, fetcher)
+
+	var witness wire.TxWitness
+	if txscript.IsPayToTaproot(pkScript) {
+		witness, err = txscript.TaprootWitnessSignature(toSign, sigHashes, 0, 0, pkScript, txscript.SigHashDefault, privKey)
+	} else {
+		witness, err = txscript.WitnessSignature(toSign, sigHashes, 0, 0, pkScript, txscript.SigHashAll, privKey, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := wire.WriteVarInt(&buf, 0, uint64(len(witness))); err != nil {
+		return nil, err
+	}
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(&buf, 0, item); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// -------------------------------
+// 🔍 Verify Message
+// -------------------------------
+
+// verifyBitcoinMessage checks a base64 BIP137 or BIP322 simple signature
+// against address.
+func verifyBitcoinMessage(address, message, signature string, isMainnet bool) bool {
+	network := bitcoinNetParams(isMainnet)
+
+	addr, err := btcutil.DecodeAddress(address, network)
+	if err != nil {
+		log.Printf("❌ Invalid address: %v", err)
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		log.Printf("❌ Signature is not valid base64: %v", err)
+		return false
+	}
+
+	// A BIP322 witness can happen to look like a BIP137 signature, so it
+	// is tried first and the legacy format only as a fallback.
+	err = verifyBIP322Simple(addr, message, sig)
+	if err == nil {
+		return true
+	}
+	if isBIP137Signature(sig) {
+		return verifyBIP137(addr, message, sig, network)
+	}
+	log.Printf("❌ Signature verification failed: %v", err)
+	return false
+}
+
+// isBIP137Signature reports whether sig has the shape of a legacy compact
+// signature: a header byte followed by r and s.
+func isBIP137Signature(sig []byte) bool {
+	return len(sig) == 65 && sig[0] >= bip137HeaderP2PKHUncompressed && sig[0] < bip137HeaderP2WPKH+4
+}
+
+func verifyBIP137(addr btcutil.Address, message string, sig []byte, network *chaincfg.Params) bool {
+	header := sig[0]
+	var scriptType string
+	switch {
+	case header >= bip137HeaderP2WPKH:
+		scriptType = ScriptP2WPKH
+	case header >= bip137HeaderP2SHP2WPKH:
+		scriptType = ScriptP2SHP2WPKH
+	default:
+		scriptType = ScriptP2PKH
+	}
+
+	// RecoverCompact only understands the P2PKH header range, so map the
+	// segwit headers onto the compressed P2PKH ones.
+	compact := append([]byte{}, sig...)
+	if header >= bip137HeaderP2SHP2WPKH {
+		compact[0] = bip137HeaderP2PKHCompressed + (header-bip137HeaderP2SHP2WPKH)%4
+	}
+	pubKey, compressed, err := ecdsa.RecoverCompact(compact, bitcoinMessageHash(message))
+	if err != nil {
+		log.Printf("❌ Failed to recover public key: %v", err)
+		return false
+	}
+
+	if !compressed {
+		recovered, err := btcutil.NewAddressPubKey(pubKey.SerializeUncompressed(), network)
+		if err != nil {
+			return false
+		}
+		return recovered.AddressPubKeyHash().EncodeAddress() == addr.EncodeAddress()
+	}
+
+	// Some wallets sign segwit addresses with the P2PKH header, so a
+	// compressed key is accepted for any single-key address type.
+	for _, candidate := range []string{scriptType, ScriptP2PKH, ScriptP2WPKH, ScriptP2SHP2WPKH} {
+		recovered, err := bitcoinAddressForPubKey(pubKey, candidate, network)
+		if err == nil && recovered.EncodeAddress() == addr.EncodeAddress() {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyBIP322Simple(addr btcutil.Address, message string, sig []byte) error {
+	r := bytes.NewReader(sig)
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return err
+	}
+	if count > uint64(r.Len()) {
+		return fmt.Errorf("witness item count %d exceeds signature length", count)
+	}
+	witness := make(wire.TxWitness, 0, count)
+	for i := uint64(0); i < count; i++ {
+		item, err := wire.ReadVarBytes(r, 0, txscript.MaxScriptSize, "witness item")
+		if err != nil {
+			return err
+		}
+		witness = append(witness, item)
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("trailing data after witness")
+	}
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+	_, toSign, err := bip322Transactions(pkScript, message)
+	if err != nil {
+		return err
+	}
+	toSign.TxIn[0].Witness = witness
+
+	fetcher := txscript.NewCannedPrevOutputFetcher(pkScript, 0)
+	engine, err := txscript.NewEngine(pkScript, toSign, 0, txscript.StandardVerifyFlags,
+		nil, txscript.NewTxSigHashes(toSign, fetcher), 0, fetcher)
+	if err != nil {
+		return err
+	}
+	return engine.Execute()
+}
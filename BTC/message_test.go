@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// BIP322 test vectors: https://github.com/bitcoin/bips/blob/master/bip-0322.mediawiki#test-vectors
+const (
+	bip322TestWIF           = "L3VFeEujGtevx9w18HD1fhRbCH67Az2dpCymeRE1SoPK6XQtaN2k"
+	bip322TestP2WPKHAddress = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+	bip322TestP2TRAddress   = "bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3"
+)
+
+func TestBIP322MessageHash(t *testing.T) {
+	tests := map[string]string{
+		"":            "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1",
+		"Hello World": "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a",
+	}
+	for message, want := range tests {
+		if got := hex.EncodeToString(bip322MessageHash(message)); got != want {
+			t.Errorf("bip322MessageHash(%q) = %s, want %s", message, got, want)
+		}
+	}
+}
+
+func TestBIP322Transactions(t *testing.T) {
+	addr, err := btcutil.DecodeAddress(bip322TestP2WPKHAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		message, toSpend, toSign string
+	}{
+		{"", "c5680aa69bb8d860bf82d4e9cd3504b55dde018de765a91bb566283c545a99a7", "1e9654e951a5ba44c8604c4de6c67fd78a27e81dcadcfe1edf638ba3aaebaed6"},
+		{"Hello World", "b79d196740ad5217771c1098fc4a4b51e0535c32236c71f1ea4d61a2d603352b", "88737ae86f2077145f93cc4b153ae9a1cb8d56afa511988c149c5c8c9d93bddf"},
+	}
+	for _, tt := range tests {
+		toSpend, toSign, err := bip322Transactions(pkScript, tt.message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := toSpend.TxHash().String(); got != tt.toSpend {
+			t.Errorf("to_spend(%q) = %s, want %s", tt.message, got, tt.toSpend)
+		}
+		if got := toSign.TxHash().String(); got != tt.toSign {
+			t.Errorf("to_sign(%q) = %s, want %s", tt.message, got, tt.toSign)
+		}
+	}
+}
+
+func TestVerifyBIP322Vectors(t *testing.T) {
+	tests := []struct {
+		address, message, signature string
+	}{
+		{bip322TestP2WPKHAddress, "", "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI="},
+		{bip322TestP2WPKHAddress, "Hello World", "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI="},
+		{bip322TestP2WPKHAddress, "Hello World", "AkgwRQIhAOzyynlqt93lOKJr+wmmxIens//zPzl9tqIOua93wO6MAiBi5n5EyAcPScOjf1lAqIUIQtr3zKNeavYabHyR8eGhowEhAsfxIAMZZEKUPYWI4BruhAQjzFT8FSFSajuFwrDL1Yhy"},
+		{bip322TestP2TRAddress, "Hello World", "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ=="},
+	}
+	for _, tt := range tests {
+		if !verifyBitcoinMessage(tt.address, tt.message, tt.signature, true) {
+			t.Errorf("signature for %q by %s did not verify", tt.message, tt.address)
+		}
+		if verifyBitcoinMessage(tt.address, tt.message+"!", tt.signature, true) {
+			t.Errorf("signature for %q by %s verified a different message", tt.message, tt.address)
+		}
+	}
+}
+
+func TestSignBitcoinMessageRoundTrip(t *testing.T) {
+	account := BitcoinAccount{WIF: bip322TestWIF}
+	tests := map[string]string{
+		ScriptP2PKH:  "",
+		ScriptP2WPKH: bip322TestP2WPKHAddress,
+		ScriptP2TR:   bip322TestP2TRAddress,
+	}
+	for scriptType, wantAddress := range tests {
+		key, _ := btcutil.DecodeWIF(bip322TestWIF)
+		addr, err := bitcoinAddressForPubKey(key.PrivKey.PubKey(), scriptType, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wantAddress != "" && addr.EncodeAddress() != wantAddress {
+			t.Fatalf("%s address = %s, want %s", scriptType, addr.EncodeAddress(), wantAddress)
+		}
+
+		signature := signBitcoinMessage(account, "Hello World", scriptType, true)
+		if !verifyBitcoinMessage(addr.EncodeAddress(), "Hello World", signature, true) {
+			t.Errorf("%s signature did not verify", scriptType)
+		}
+		if verifyBitcoinMessage(addr.EncodeAddress(), "Hello World!", signature, true) {
+			t.Errorf("%s signature verified a different message", scriptType)
+		}
+	}
+}
+
+func TestVerifyBIP137SegwitHeaders(t *testing.T) {
+	account := BitcoinAccount{WIF: bip322TestWIF}
+	signature := signBitcoinMessage(account, "Hello World", ScriptP2PKH, true)
+	sig, _ := base64.StdEncoding.DecodeString(signature)
+	recoveryID := (sig[0] - bip137HeaderP2PKHCompressed) % 4
+
+	key, _ := btcutil.DecodeWIF(bip322TestWIF)
+	for scriptType, header := range map[string]byte{
+		ScriptP2SHP2WPKH: bip137HeaderP2SHP2WPKH,
+		ScriptP2WPKH:     bip137HeaderP2WPKH,
+	} {
+		addr, err := bitcoinAddressForPubKey(key.PrivKey.PubKey(), scriptType, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig[0] = header + recoveryID
+		if !verifyBitcoinMessage(addr.EncodeAddress(), "Hello World", base64.StdEncoding.EncodeToString(sig), true) {
+			t.Errorf("BIP137 %s signature did not verify", scriptType)
+		}
+	}
+}
+
+func TestVerifyBitcoinMessageRejectsGarbage(t *testing.T) {
+	for _, signature := range []string{"", "not base64", base64.StdEncoding.EncodeToString([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})} {
+		if verifyBitcoinMessage(bip322TestP2WPKHAddress, "Hello World", signature, true) {
+			t.Errorf("signature %q verified", signature)
+		}
+	}
+}
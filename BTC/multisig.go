@@ -0,0 +1,551 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// -------------------------------
+// Multisig Wallet Structures
+// -------------------------------
+
+// MultisigCosigner is one cosigner's account-level extended public key
+// together with its key origin (master fingerprint and hardened path).
+type MultisigCosigner struct {
+	Key         *hdkeychain.ExtendedKey
+	Fingerprint uint32
+	OriginPath  []uint32
+}
+
+// MultisigWallet is an m-of-n wallet whose addresses commit to the
+// cosigners' child keys sorted as in BIP67.
+type MultisigWallet struct {
+	Threshold int
+	Cosigners []MultisigCosigner
+	Nested    bool // P2SH-P2WSH instead of native P2WSH
+	IsMainnet bool
+}
+
+type MultisigAddress struct {
+	Address       btcutil.Address
+	PkScript      []byte
+	WitnessScript []byte
+	RedeemScript  []byte // only set for P2SH-P2WSH
+	Derivations   []*psbt.Bip32Derivation
+}
+
+// SLIP-132 multisig extended public key versions.
+var multisigPubKeyVersions = map[[4]byte]bool{
+	{0x04, 0x88, 0xb2, 0x1e}: true,  // xpub
+	{0x02, 0x95, 0xb4, 0x3f}: true,  // Ypub
+	{0x02, 0xaa, 0x7e, 0xd3}: true,  // Zpub
+	{0x04, 0x35, 0x87, 0xcf}: false, // tpub
+	{0x02, 0x42, 0x89, 0xef}: false, // Upub
+	{0x02, 0x57, 0x54, 0x83}: false, // Vpub
+}
+
+// -------------------------------
+// 🧬 Create Multisig Wallet
+// -------------------------------
+
+// createMultisigWallet builds an m-of-n wallet from cosigner xpubs. Each
+// xpub may carry a key origin, e.g. "[d34db33f/48'/0'/0'/2']xpub...";
+// without one the xpub's own fingerprint is used.
+func createMultisigWallet(threshold int, cosignerXpubs []string, nested bool) MultisigWallet {
+	if threshold < 1 || threshold > len(cosignerXpubs) {
+		log.Fatalf("❌ Invalid threshold %d for %d cosigners", threshold, len(cosignerXpubs))
+	}
+	if len(cosignerXpubs) > 15 {
+		log.Fatalf("❌ At most 15 cosigners are standard, got %d", len(cosignerXpubs))
+	}
+
+	wallet := MultisigWallet{Threshold: threshold, Nested: nested}
+	for i, xpub := range cosignerXpubs {
+		cosigner := parseMultisigCosigner(xpub)
+
+		var version [4]byte
+		copy(version[:], cosigner.Key.Version())
+		isMainnet, ok := multisigPubKeyVersions[version]
+		if !ok {
+			log.Fatalf("❌ Unknown extended public key version %x for cosigner %d", version, i+1)
+		}
+		if i > 0 && isMainnet != wallet.IsMainnet {
+			log.Fatal("❌ Cosigner keys are for different networks")
+		}
+		wallet.IsMainnet = isMainnet
+		wallet.Cosigners = append(wallet.Cosigners, cosigner)
+	}
+	return wallet
+}
+
+func parseMultisigCosigner(xpub string) MultisigCosigner {
+	var cosigner MultisigCosigner
+	xpub = strings.TrimSpace(xpub)
+
+	if strings.HasPrefix(xpub, "[") {
+		end := strings.IndexByte(xpub, ']')
+		if end < 0 {
+			log.Fatalf("❌ Unterminated key origin: %s", xpub)
+		}
+		origin := strings.Split(xpub[1:end], "/")
+		fingerprint, err := hex.DecodeString(origin[0])
+		if err != nil || len(fingerprint) != 4 {
+			log.Fatalf("❌ Invalid key origin fingerprint %q", origin[0])
+		}
+		// PSBT fingerprints are the raw bytes read as little-endian.
+		cosigner.Fingerprint = binary.LittleEndian.Uint32(fingerprint)
+		for _, step := range origin[1:] {
+			cosigner.OriginPath = append(cosigner.OriginPath, parseOriginStep(step))
+		}
+		xpub = xpub[end+1:]
+	}
+
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		log.Fatalf("❌ Invalid cosigner xpub: %v", err)
+	}
+	if key.IsPrivate() {
+		log.Fatal("❌ Cosigner keys must be extended public keys")
+	}
+	cosigner.Key = key
+
+	if cosigner.OriginPath == nil && cosigner.Fingerprint == 0 {
+		cosigner.Fingerprint = keyFingerprint(key)
+	}
+	return cosigner
+}
+
+func parseOriginStep(step string) uint32 {
+	hardened := strings.HasSuffix(step, "'") || strings.HasSuffix(step, "h")
+	index, err := strconv.ParseUint(strings.TrimRight(step, "'h"), 10, 31)
+	if err != nil {
+		log.Fatalf("❌ Invalid key origin step %q: %v", step, err)
+	}
+	if hardened {
+		return uint32(index) + hdkeychain.HardenedKeyStart
+	}
+	return uint32(index)
+}
+
+// keyFingerprint is the first four bytes of HASH160(pubkey), in the
+// little-endian form used by PSBT derivation records.
+func keyFingerprint(key *hdkeychain.ExtendedKey) uint32 {
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		log.Fatalf("❌ Failed to read public key: %v", err)
+	}
+	return binary.LittleEndian.Uint32(btcutil.Hash160(pubKey.SerializeCompressed())[:4])
+}
+
+// -------------------------------
+// 🏦 Multisig Addresses
+// -------------------------------
+func (w MultisigWallet) deriveAddress(chain, index uint32) MultisigAddress {
+	network := bitcoinNetParams(w.IsMainnet)
+
+	type cosignerKey struct {
+		pubKey     []byte
+		derivation *psbt.Bip32Derivation
+	}
+	keys := make([]cosignerKey, 0, len(w.Cosigners))
+	for _, cosigner := range w.Cosigners {
+		child, err := cosigner.Key.Derive(chain)
+		if err == nil {
+			child, err = child.Derive(index)
+		}
+		if err != nil {
+			log.Fatalf("❌ Failed to derive cosigner key %d/%d: %v", chain, index, err)
+		}
+		pubKey, err := child.ECPubKey()
+		if err != nil {
+			log.Fatalf("❌ Failed to read cosigner public key: %v", err)
+		}
+		path := append(append([]uint32{}, cosigner.OriginPath...), chain, index)
+		keys = append(keys, cosignerKey{
+			pubKey: pubKey.SerializeCompressed(),
+			derivation: &psbt.Bip32Derivation{
+				PubKey:               pubKey.SerializeCompressed(),
+				MasterKeyFingerprint: cosigner.Fingerprint,
+				Bip32Path:            path,
+			},
+		})
+	}
+
+	// BIP67: lexicographically sorted compressed public keys.
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].pubKey, keys[j].pubKey) < 0
+	})
+
+	addrPubKeys := make([]*btcutil.AddressPubKey, 0, len(keys))
+	derivations := make([]*psbt.Bip32Derivation, 0, len(keys))
+	for _, key := range keys {
+		addrPubKey, err := btcutil.NewAddressPubKey(key.pubKey, network)
+		if err != nil {
+			log.Fatalf("❌ Invalid cosigner public key: %v", err)
+		}
+		addrPubKeys = append(addrPubKeys, addrPubKey)
+		derivations = append(derivations, key.derivation)
+	}
+
+	witnessScript, err := txscript.MultiSigScript(addrPubKeys, w.Threshold)
+	if err != nil {
+		log.Fatalf("❌ Failed to build multisig script: %v", err)
+	}
+	scriptHash := chainhash.HashB(witnessScript)
+	witnessAddr, err := btcutil.NewAddressWitnessScriptHash(scriptHash, network)
+	if err != nil {
+		log.Fatalf("❌ Failed to create P2WSH address: %v", err)
+	}
+
+	result := MultisigAddress{
+		Address:       witnessAddr,
+		WitnessScript: witnessScript,
+		Derivations:   derivations,
+	}
+	if w.Nested {
+		redeemScript, err := txscript.PayToAddrScript(witnessAddr)
+		if err != nil {
+			log.Fatalf("❌ Failed to create redeem script: %v", err)
+		}
+		nestedAddr, err := btcutil.NewAddressScriptHash(redeemScript, network)
+		if err != nil {
+			log.Fatalf("❌ Failed to create P2SH-P2WSH address: %v", err)
+		}
+		result.Address = nestedAddr
+		result.RedeemScript = redeemScript
+	}
+
+	result.PkScript, err = txscript.PayToAddrScript(result.Address)
+	if err != nil {
+		log.Fatalf("❌ Failed to create output script: %v", err)
+	}
+	return result
+}
+
+// multisigInputVSize estimates the virtual size of one m-of-n input.
+func (w MultisigWallet) multisigInputVSize() int64 {
+	witnessScriptLen := int64(3 + 34*len(w.Cosigners))
+	witness := 1 + 1 + int64(w.Threshold)*73 + 1 + witnessScriptLen
+	base := int64(32 + 4 + 1 + 4)
+	if w.Nested {
+		base += 35
+	}
+	return base + (witness+3)/4
+}
+
+// -------------------------------
+// 📝 Create Multisig PSBT
+// -------------------------------
+
+// createMultisigPSBT spends every UTXO of the given multisig addresses to
+// toAddress, returning change to changeAddr. The result is a base64 PSBT
+// that each cosigner signs with signMultisigPSBT.
+func createMultisigPSBT(backend BitcoinBackend, wallet MultisigWallet, from []MultisigAddress, changeAddr MultisigAddress, toAddress string, amountBTC float64, feeRate int64) string {
+	network := backend.Params()
+
+	var (
+		outPoints []*wire.OutPoint
+		prevOuts  []*wire.TxOut
+		sources   []MultisigAddress
+		total     int64
+	)
+	for _, addr := range from {
+		utxos, err := backend.ListUnspent(addr.Address.EncodeAddress())
+		if err != nil {
+			log.Fatalf("❌ Failed to get UTXOs for %s: %v", addr.Address.EncodeAddress(), err)
+		}
+		for _, utxo := range utxos {
+			hash, err := chainhash.NewHashFromStr(utxo.TxID)
+			if err != nil {
+				log.Fatalf("❌ Invalid UTXO txid: %v", err)
+			}
+			outPoints = append(outPoints, wire.NewOutPoint(hash, utxo.Vout))
+			prevOuts = append(prevOuts, wire.NewTxOut(utxo.Value, addr.PkScript))
+			sources = append(sources, addr)
+			total += utxo.Value
+		}
+	}
+	if len(outPoints) == 0 {
+		log.Fatal("❌ No UTXOs found for the multisig addresses")
+	}
+
+	toAddr, err := btcutil.DecodeAddress(toAddress, network)
+	if err != nil {
+		log.Fatalf("❌ Invalid recipient address: %v", err)
+	}
+	toScript, err := txscript.PayToAddrScript(toAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to create output script: %v", err)
+	}
+
+	amountSat := int64(amountBTC * 1e8)
+	vsize := 11 + int64(len(outPoints))*wallet.multisigInputVSize() + 2*43
+	fee := vsize * feeRate
+	change := total - amountSat - fee
+	if change < 0 {
+		log.Fatalf("❌ Insufficient funds: have %d sat, need %d sat", total, amountSat+fee)
+	}
+
+	// Change below the dust limit would not relay; it is left to the miner
+	outputs := []*wire.TxOut{wire.NewTxOut(amountSat, toScript)}
+	if change >= dustLimit {
+		outputs = append(outputs, wire.NewTxOut(change, changeAddr.PkScript))
+	}
+
+	sequences := make([]uint32, len(outPoints))
+	for i := range sequences {
+		sequences[i] = wire.MaxTxInSequenceNum
+	}
+	packet, err := psbt.New(outPoints, outputs, 2, 0, sequences)
+	if err != nil {
+		log.Fatalf("❌ Failed to create PSBT: %v", err)
+	}
+
+	updater, err := psbt.NewUpdater(packet)
+	if err != nil {
+		log.Fatalf("❌ Failed to create PSBT updater: %v", err)
+	}
+	for i, source := range sources {
+		if err := updater.AddInWitnessUtxo(prevOuts[i], i); err != nil {
+			log.Fatalf("❌ Failed to add witness UTXO: %v", err)
+		}
+		if err := updater.AddInWitnessScript(source.WitnessScript, i); err != nil {
+			log.Fatalf("❌ Failed to add witness script: %v", err)
+		}
+		if source.RedeemScript != nil {
+			if err := updater.AddInRedeemScript(source.RedeemScript, i); err != nil {
+				log.Fatalf("❌ Failed to add redeem script: %v", err)
+			}
+		}
+		if err := updater.AddInSighashType(txscript.SigHashAll, i); err != nil {
+			log.Fatalf("❌ Failed to add sighash type: %v", err)
+		}
+		for _, d := range source.Derivations {
+			if err := updater.AddInBip32Derivation(d.MasterKeyFingerprint, d.Bip32Path, d.PubKey, i); err != nil {
+				log.Fatalf("❌ Failed to add input derivation: %v", err)
+			}
+		}
+	}
+
+	// Describe the change output so cosigners can verify it is ours.
+	if change > 0 {
+		changeIdx := len(outputs) - 1
+		if err := updater.AddOutWitnessScript(changeAddr.WitnessScript, changeIdx); err != nil {
+			log.Fatalf("❌ Failed to add change witness script: %v", err)
+		}
+		if changeAddr.RedeemScript != nil {
+			if err := updater.AddOutRedeemScript(changeAddr.RedeemScript, changeIdx); err != nil {
+				log.Fatalf("❌ Failed to add change redeem script: %v", err)
+			}
+		}
+		for _, d := range changeAddr.Derivations {
+			if err := updater.AddOutBip32Derivation(d.MasterKeyFingerprint, d.Bip32Path, d.PubKey, changeIdx); err != nil {
+				log.Fatalf("❌ Failed to add change derivation: %v", err)
+			}
+		}
+	}
+
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		log.Fatalf("❌ Failed to encode PSBT: %v", err)
+	}
+
+	fmt.Printf("✅ Multisig PSBT created (%d inputs, fee %d sat)\n", len(outPoints), fee)
+	return encoded
+}
+
+// -------------------------------
+// ✍️ Cosigner Signing
+// -------------------------------
+
+// signMultisigPSBT adds this cosigner's signatures to every input it has
+// a key for. extendedPrivKey may be the master key or the account-level
+// key matching the cosigner xpub.
+func signMultisigPSBT(psbtBase64, extendedPrivKey string) string {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(psbtBase64), true)
+	if err != nil {
+		log.Fatalf("❌ Invalid PSBT: %v", err)
+	}
+
+	signingKey, err := hdkeychain.NewKeyFromString(extendedPrivKey)
+	if err != nil {
+		log.Fatalf("❌ Invalid extended private key: %v", err)
+	}
+	if !signingKey.IsPrivate() {
+		log.Fatal("❌ Signing requires an extended private key")
+	}
+	fingerprint := keyFingerprint(signingKey)
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for i, txIn := range packet.UnsignedTx.TxIn {
+		if packet.Inputs[i].WitnessUtxo == nil {
+			log.Fatalf("❌ Input %d is missing its witness UTXO", i)
+		}
+		prevOuts[txIn.PreviousOutPoint] = packet.Inputs[i].WitnessUtxo
+	}
+	sigHashes := txscript.NewTxSigHashes(packet.UnsignedTx, txscript.NewMultiPrevOutFetcher(prevOuts))
+
+	updater, err := psbt.NewUpdater(packet)
+	if err != nil {
+		log.Fatalf("❌ Failed to create PSBT updater: %v", err)
+	}
+
+	signed := 0
+	for i, input := range packet.Inputs {
+		for _, d := range input.Bip32Derivation {
+			path, ok := signingPathFor(signingKey, fingerprint, d)
+			if !ok {
+				continue
+			}
+
+			child := signingKey
+			for _, step := range path {
+				child, err = child.Derive(step)
+				if err != nil {
+					log.Fatalf("❌ Failed to derive signing key: %v", err)
+				}
+			}
+			privKey, err := child.ECPrivKey()
+			if err != nil {
+				log.Fatalf("❌ Failed to read signing key: %v", err)
+			}
+			if !bytes.Equal(privKey.PubKey().SerializeCompressed(), d.PubKey) {
+				continue
+			}
+
+			sig, err := txscript.RawTxInWitnessSignature(packet.UnsignedTx, sigHashes, i,
+				input.WitnessUtxo.Value, input.WitnessScript, txscript.SigHashAll, privKey)
+			if err != nil {
+				log.Fatalf("❌ Failed to sign input %d: %v", i, err)
+			}
+			if _, err := updater.Sign(i, sig, d.PubKey, nil, nil); err != nil {
+				log.Fatalf("❌ Failed to add signature to input %d: %v", i, err)
+			}
+			signed++
+		}
+	}
+	if signed == 0 {
+		log.Fatal("❌ This key does not belong to any cosigner of the PSBT")
+	}
+
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		log.Fatalf("❌ Failed to encode PSBT: %v", err)
+	}
+
+	fmt.Printf("✅ Added %d signature(s)\n", signed)
+	return encoded
+}
+
+// signingPathFor returns the derivation steps from signingKey to the key
+// in d. A master key follows the full path; an account-level key only
+// the trailing chain/index steps. Callers compare the derived public key.
+func signingPathFor(signingKey *hdkeychain.ExtendedKey, fingerprint uint32, d *psbt.Bip32Derivation) ([]uint32, bool) {
+	if signingKey.Depth() == 0 {
+		return d.Bip32Path, d.MasterKeyFingerprint == fingerprint
+	}
+	if len(d.Bip32Path) < 2 {
+		return nil, false
+	}
+	return d.Bip32Path[len(d.Bip32Path)-2:], true
+}
+
+// -------------------------------
+// 🚀 Finalize and Broadcast
+// -------------------------------
+
+// combineMultisigPSBTs merges partial signatures from several cosigners'
+// copies of the same PSBT.
+func combineMultisigPSBTs(psbts []string) string {
+	if len(psbts) == 0 {
+		log.Fatal("❌ No PSBTs to combine")
+	}
+	base, err := psbt.NewFromRawBytes(strings.NewReader(psbts[0]), true)
+	if err != nil {
+		log.Fatalf("❌ Invalid PSBT: %v", err)
+	}
+	baseTxID := base.UnsignedTx.TxHash()
+
+	for _, encoded := range psbts[1:] {
+		other, err := psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+		if err != nil {
+			log.Fatalf("❌ Invalid PSBT: %v", err)
+		}
+		if other.UnsignedTx.TxHash() != baseTxID {
+			log.Fatal("❌ PSBTs spend different transactions")
+		}
+		for i := range other.Inputs {
+		next:
+			for _, sig := range other.Inputs[i].PartialSigs {
+				for _, existing := range base.Inputs[i].PartialSigs {
+					if bytes.Equal(existing.PubKey, sig.PubKey) {
+						continue next
+					}
+				}
+				base.Inputs[i].PartialSigs = append(base.Inputs[i].PartialSigs, sig)
+			}
+		}
+	}
+
+	encoded, err := base.B64Encode()
+	if err != nil {
+		log.Fatalf("❌ Failed to encode PSBT: %v", err)
+	}
+	return encoded
+}
+
+// finalizeMultisigPSBT checks that every input has reached the threshold,
+// builds the final witnesses and broadcasts the transaction.
+func finalizeMultisigPSBT(backend BitcoinBackend, psbtBase64 string) string {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(psbtBase64), true)
+	if err != nil {
+		log.Fatalf("❌ Invalid PSBT: %v", err)
+	}
+
+	for i, input := range packet.Inputs {
+		_, threshold, err := txscript.CalcMultiSigStats(input.WitnessScript)
+		if err != nil {
+			log.Fatalf("❌ Input %d is not a multisig input: %v", i, err)
+		}
+		if len(input.PartialSigs) < threshold {
+			log.Fatalf("❌ Input %d has %d of %d required signatures", i, len(input.PartialSigs), threshold)
+		}
+		// The finalizer wants exactly m signatures; keep those whose keys
+		// come first in the script.
+		if len(input.PartialSigs) > threshold {
+			sort.Slice(input.PartialSigs, func(a, b int) bool {
+				return bytes.Compare(input.PartialSigs[a].PubKey, input.PartialSigs[b].PubKey) < 0
+			})
+			packet.Inputs[i].PartialSigs = input.PartialSigs[:threshold]
+		}
+	}
+
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		log.Fatalf("❌ Failed to finalize PSBT: %v", err)
+	}
+	tx, err := psbt.Extract(packet)
+	if err != nil {
+		log.Fatalf("❌ Failed to extract transaction: %v", err)
+	}
+
+	txid, err := backend.Broadcast(tx)
+	if err != nil {
+		log.Fatalf("❌ Failed to broadcast transaction: %v", err)
+	}
+
+	fmt.Printf("✅ Multisig transaction sent successfully!\n🔗 TxID: %s\n", txid)
+	return txid
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// dustLimit is the smallest P2PKH/P2WPKH output relayed by default.
+const dustLimit = 546
+
+// -------------------------------
+// 🧹 Consolidate UTXOs
+// -------------------------------
+
+// consolidateBitcoinUTXOs merges the account's confirmed UTXOs smaller
+// than thresholdBTC into a single output back to the same address. It
+// does nothing when the current fee rate is above maxFeeRate (sat/vB)
+// and returns the txid, or "" when nothing was sent.
+func consolidateBitcoinUTXOs(backend BitcoinBackend, wif string, thresholdBTC float64, maxFeeRate int64) string {
+	key, fromAddress := decodeBitcoinWIF(wif, backend)
+
+	feeRate, err := backend.EstimateFeeRate(6)
+	if err != nil {
+		log.Printf("❌ Fee estimation failed, not consolidating: %v", err)
+		return ""
+	}
+	if feeRate > maxFeeRate {
+		fmt.Printf("⏳ Fee rate %d sat/vB is above %d sat/vB, consolidation postponed\n", feeRate, maxFeeRate)
+		return ""
+	}
+
+	utxos, err := backend.ListUnspent(fromAddress.EncodeAddress())
+	if err != nil {
+		log.Fatalf("❌ Failed to get UTXOs: %v", err)
+	}
+
+	threshold := int64(thresholdBTC * 1e8)
+	var small []BitcoinUTXOResponse
+	for _, utxo := range utxos {
+		if utxo.Status.Confirmed && utxo.Value < threshold {
+			small = append(small, utxo)
+		}
+	}
+	if len(small) < 2 {
+		fmt.Printf("✅ Nothing to consolidate: %d UTXO(s) below %.8f BTC\n", len(small), thresholdBTC)
+		return ""
+	}
+
+	toScript, err := txscript.PayToAddrScript(fromAddress)
+	if err != nil {
+		log.Fatalf("❌ Failed to create output script: %v", err)
+	}
+
+	txid := spendP2PKHUTXOs(backend, key, small, toScript, feeRate)
+	fmt.Printf("✅ Consolidated %d UTXOs\n🔗 TxID: %s\n", len(small), txid)
+	return txid
+}
+
+// -------------------------------
+// 🧺 Sweep Account
+// -------------------------------
+
+// sweepBitcoinAccount sends the full spendable balance of a WIF to
+// toAddress with the fee deducted from the amount.
+func sweepBitcoinAccount(backend BitcoinBackend, wif, toAddress string) string {
+	key, fromAddress := decodeBitcoinWIF(wif, backend)
+
+	utxos, err := backend.ListUnspent(fromAddress.EncodeAddress())
+	if err != nil {
+		log.Fatalf("❌ Failed to get UTXOs: %v", err)
+	}
+	if len(utxos) == 0 {
+		log.Fatalf("❌ No UTXOs found for address %s", fromAddress.EncodeAddress())
+	}
+
+	toAddr, err := btcutil.DecodeAddress(toAddress, backend.Params())
+	if err != nil {
+		log.Fatalf("❌ Invalid recipient address: %v", err)
+	}
+	toScript, err := txscript.PayToAddrScript(toAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to create output script: %v", err)
+	}
+
+	txid := spendP2PKHUTXOs(backend, key, utxos, toScript, estimateFeeRate(backend))
+	fmt.Printf("✅ Swept %d UTXOs to %s\n🔗 TxID: %s\n", len(utxos), toAddress, txid)
+	return txid
+}
+
+// -------------------------------
+// ⚙️ Spend Helpers
+// -------------------------------
+func decodeBitcoinWIF(wif string, backend BitcoinBackend) (*btcutil.WIF, *btcutil.AddressPubKey) {
+	key, err := btcutil.DecodeWIF(wif)
+	if err != nil {
+		log.Fatalf("❌ Invalid WIF: %v", err)
+	}
+	address, err := btcutil.NewAddressPubKey(key.PrivKey.PubKey().SerializeCompressed(), backend.Params())
+	if err != nil {
+		log.Fatalf("❌ Failed to generate from address: %v", err)
+	}
+	return key, address
+}
+
+// spendP2PKHUTXOs spends utxos of the key's P2PKH address to a single
+// output carrying the total minus the fee, and broadcasts it.
+func spendP2PKHUTXOs(backend BitcoinBackend, key *btcutil.WIF, utxos []BitcoinUTXOResponse, toScript []byte, feeRate int64) string {
+	fromAddress, err := btcutil.NewAddressPubKey(key.PrivKey.PubKey().SerializeCompressed(), backend.Params())
+	if err != nil {
+		log.Fatalf("❌ Failed to generate from address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(fromAddress)
+	if err != nil {
+		log.Fatalf("❌ Failed to generate pkScript: %v", err)
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	var total int64
+	for _, utxo := range utxos {
+		hash, err := chainhash.NewHashFromStr(utxo.TxID)
+		if err != nil {
+			log.Fatalf("❌ Invalid UTXO txid: %v", err)
+		}
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(hash, utxo.Vout), nil, nil))
+		total += utxo.Value
+	}
+
+	vsize := int64(10 + 148*len(tx.TxIn) + len(toScript) + 9)
+	fee := vsize * feeRate
+	if total-fee < dustLimit {
+		log.Fatalf("❌ Balance of %d sat does not cover the %d sat fee", total, fee)
+	}
+	tx.AddTxOut(wire.NewTxOut(total-fee, toScript))
+
+	for i, txIn := range tx.TxIn {
+		sigScript, err := txscript.SignatureScript(tx, i, pkScript, txscript.SigHashAll, key.PrivKey, true)
+		if err != nil {
+			log.Fatalf("❌ Failed to sign transaction: %v", err)
+		}
+		txIn.SignatureScript = sigScript
+	}
+
+	txid, err := backend.Broadcast(tx)
+	if err != nil {
+		log.Fatalf("❌ Failed to broadcast transaction: %v", err)
+	}
+	return txid
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// -------------------------------
+// Watch-only Wallet Structures
+// -------------------------------
+
+// Script types supported for address derivation.
+const (
+	ScriptP2PKH      = "p2pkh"
+	ScriptP2SHP2WPKH = "p2sh-p2wpkh"
+	ScriptP2WPKH     = "p2wpkh"
+	ScriptP2TR       = "p2tr"
+)
+
+// DefaultGapLimit is the BIP44 address gap limit.
+const DefaultGapLimit = 20
+
+// WatchOnlyWallet holds an account-level extended public key and the
+// script type used to turn derived keys into addresses.
+type WatchOnlyWallet struct {
+	AccountKey *hdkeychain.ExtendedKey
+	ScriptType string
+	IsMainnet  bool
+	// ReceivePath and ChangePath are the non-hardened steps below the
+	// account key, without the final address index.
+	ReceivePath []uint32
+	ChangePath  []uint32
+}
+
+type WatchOnlyAddress struct {
+	Address string
+	Chain   string // "receive" or "change"
+	Index   uint32
+	TxCount int
+	Balance int64
+	UTXOs   []BitcoinUTXOResponse
+}
+
+type WatchOnlyScan struct {
+	Addresses      []WatchOnlyAddress
+	Confirmed      int64
+	Unconfirmed    int64
+	NextReceive    string
+	NextReceiveIdx uint32
+	NextChange     string
+	NextChangeIdx  uint32
+}
+
+type esploraAddressStats struct {
+	ChainStats struct {
+		TxCount int `json:"tx_count"`
+	} `json:"chain_stats"`
+	MempoolStats struct {
+		TxCount int `json:"tx_count"`
+	} `json:"mempool_stats"`
+}
+
+// SLIP-132 extended public key versions.
+var extendedPubKeyVersions = map[[4]byte]struct {
+	scriptType string
+	isMainnet  bool
+}{
+	{0x04, 0x88, 0xb2, 0x1e}: {ScriptP2PKH, true},       // xpub
+	{0x04, 0x9d, 0x7c, 0xab}: {ScriptP2SHP2WPKH, true},  // ypub
+	{0x04, 0xb2, 0x47, 0x46}: {ScriptP2WPKH, true},      // zpub
+	{0x04, 0x35, 0x87, 0xcf}: {ScriptP2PKH, false},      // tpub
+	{0x04, 0x4a, 0x52, 0x62}: {ScriptP2SHP2WPKH, false}, // upub
+	{0x04, 0x5f, 0x1c, 0xf6}: {ScriptP2WPKH, false},     // vpub
+}
+
+// -------------------------------
+// 🌐 Network Helpers
+// -------------------------------
+func bitcoinNetParams(isMainnet bool) *chaincfg.Params {
+	if isMainnet {
+		return &chaincfg.MainNetParams
+	}
+	return &chaincfg.TestNet3Params
+}
+
+// bitcoinAddressForPubKey encodes a public key as an address of the
+// given script type.
+func bitcoinAddressForPubKey(pubKey *btcec.PublicKey, scriptType string, network *chaincfg.Params) (btcutil.Address, error) {
+	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
+	switch scriptType {
+	case ScriptP2PKH:
+		return btcutil.NewAddressPubKeyHash(pubKeyHash, network)
+	case ScriptP2WPKH:
+		return btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, network)
+	case ScriptP2SHP2WPKH:
+		witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, network)
+		if err != nil {
+			return nil, err
+		}
+		redeemScript, err := txscript.PayToAddrScript(witnessAddr)
+		if err != nil {
+			return nil, err
+		}
+		return btcutil.NewAddressScriptHash(redeemScript, network)
+	case ScriptP2TR:
+		outputKey := txscript.ComputeTaprootKeyNoScript(pubKey)
+		return btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), network)
+	}
+	return nil, fmt.Errorf("unsupported script type %q", scriptType)
+}
+
+// -------------------------------
+// 📥 Import Extended Public Key
+// -------------------------------
+func importExtendedPublicKey(extendedKey string) WatchOnlyWallet {
+	key, err := hdkeychain.NewKeyFromString(extendedKey)
+	if err != nil {
+		log.Fatalf("❌ Invalid extended public key: %v", err)
+	}
+	if key.IsPrivate() {
+		log.Fatal("❌ Refusing to import an extended private key into a watch-only wallet")
+	}
+
+	var version [4]byte
+	copy(version[:], key.Version())
+	info, ok := extendedPubKeyVersions[version]
+	if !ok {
+		log.Fatalf("❌ Unknown extended public key version %x", version)
+	}
+
+	return WatchOnlyWallet{
+		AccountKey:  key,
+		ScriptType:  info.scriptType,
+		IsMainnet:   info.isMainnet,
+		ReceivePath: []uint32{0},
+		ChangePath:  []uint32{1},
+	}
+}
+
+// importOutputDescriptor accepts single-key descriptors such as
+// "wpkh([d34db33f/84'/0'/0']xpub.../0/*)#checksum". The change chain is
+// taken from a "<0;1>" multipath step, or else derived by replacing the
+// receive step with 1.
+func importOutputDescriptor(descriptor string) WatchOnlyWallet {
+	descriptor = strings.TrimSpace(descriptor)
+	if i := strings.IndexByte(descriptor, '#'); i >= 0 {
+		checksum := descriptor[i+1:]
+		descriptor = descriptor[:i]
+		if descriptorChecksum(descriptor) != checksum {
+			log.Fatalf("❌ Descriptor checksum mismatch: expected %s", descriptorChecksum(descriptor))
+		}
+	}
+
+	scriptType := ""
+	inner := descriptor
+	switch {
+	case strings.HasPrefix(inner, "sh(wpkh(") && strings.HasSuffix(inner, "))"):
+		scriptType, inner = ScriptP2SHP2WPKH, inner[len("sh(wpkh("):len(inner)-2]
+	case strings.HasPrefix(inner, "wpkh(") && strings.HasSuffix(inner, ")"):
+		scriptType, inner = ScriptP2WPKH, inner[len("wpkh("):len(inner)-1]
+	case strings.HasPrefix(inner, "pkh(") && strings.HasSuffix(inner, ")"):
+		scriptType, inner = ScriptP2PKH, inner[len("pkh("):len(inner)-1]
+	case strings.HasPrefix(inner, "tr(") && strings.HasSuffix(inner, ")"):
+		scriptType, inner = ScriptP2TR, inner[len("tr("):len(inner)-1]
+	default:
+		log.Fatalf("❌ Unsupported output descriptor: %s", descriptor)
+	}
+
+	// Strip the key origin, it does not affect address derivation.
+	if strings.HasPrefix(inner, "[") {
+		end := strings.IndexByte(inner, ']')
+		if end < 0 {
+			log.Fatalf("❌ Unterminated key origin in descriptor: %s", descriptor)
+		}
+		inner = inner[end+1:]
+	}
+
+	parts := strings.Split(inner, "/")
+	if len(parts) < 2 || parts[len(parts)-1] != "*" {
+		log.Fatalf("❌ Descriptor key must end in a ranged /* step: %s", descriptor)
+	}
+
+	key, err := hdkeychain.NewKeyFromString(parts[0])
+	if err != nil {
+		log.Fatalf("❌ Invalid extended public key in descriptor: %v", err)
+	}
+	if key.IsPrivate() {
+		log.Fatal("❌ Refusing to import an extended private key into a watch-only wallet")
+	}
+
+	var receivePath, changePath []uint32
+	steps := parts[1 : len(parts)-1]
+	multipath := false
+	for _, step := range steps {
+		if strings.HasPrefix(step, "<") && strings.HasSuffix(step, ">") {
+			choices := strings.Split(step[1:len(step)-1], ";")
+			if len(choices) != 2 || multipath {
+				log.Fatalf("❌ Only a single two-way multipath step is supported: %s", step)
+			}
+			receivePath = append(receivePath, parseDescriptorStep(choices[0]))
+			changePath = append(changePath, parseDescriptorStep(choices[1]))
+			multipath = true
+			continue
+		}
+		index := parseDescriptorStep(step)
+		receivePath = append(receivePath, index)
+		changePath = append(changePath, index)
+	}
+	if !multipath {
+		if len(receivePath) == 0 {
+			log.Fatalf("❌ Descriptor has no chain step before /*: %s", descriptor)
+		}
+		changePath[len(changePath)-1] = 1
+	}
+
+	// BIP380 key expressions are xpub/tpub; the script type comes from
+	// the descriptor, so SLIP-132 ypub/zpub style versions are rejected
+	var version [4]byte
+	copy(version[:], key.Version())
+	info, ok := extendedPubKeyVersions[version]
+	if !ok || info.scriptType != ScriptP2PKH {
+		log.Fatalf("❌ Descriptor keys must be xpub or tpub, got version %x", version)
+	}
+	return WatchOnlyWallet{
+		AccountKey:  key,
+		ScriptType:  scriptType,
+		IsMainnet:   info.isMainnet,
+		ReceivePath: receivePath,
+		ChangePath:  changePath,
+	}
+}
+
+func parseDescriptorStep(step string) uint32 {
+	if strings.HasSuffix(step, "'") || strings.HasSuffix(step, "h") {
+		log.Fatalf("❌ Hardened step %s cannot be derived from a public key", step)
+	}
+	index, err := strconv.ParseUint(step, 10, 31)
+	if err != nil {
+		log.Fatalf("❌ Invalid derivation step %q: %v", step, err)
+	}
+	return uint32(index)
+}
+
+// descriptorChecksum implements the BIP380 descriptor checksum.
+func descriptorChecksum(descriptor string) string {
+	const inputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	const checksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	polymod := func(c uint64, val int) uint64 {
+		c0 := c >> 35
+		c = ((c & 0x7ffffffff) << 5) ^ uint64(val)
+		for i, gen := range []uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd} {
+			if c0&(1<<uint(i)) != 0 {
+				c ^= gen
+			}
+		}
+		return c
+	}
+
+	c := uint64(1)
+	cls, clsCount := 0, 0
+	for _, ch := range descriptor {
+		pos := strings.IndexRune(inputCharset, ch)
+		if pos < 0 {
+			return ""
+		}
+		c = polymod(c, pos&31)
+		cls = cls*3 + (pos >> 5)
+		clsCount++
+		if clsCount == 3 {
+			c = polymod(c, cls)
+			cls, clsCount = 0, 0
+		}
+	}
+	if clsCount > 0 {
+		c = polymod(c, cls)
+	}
+	for i := 0; i < 8; i++ {
+		c = polymod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := 0; i < 8; i++ {
+		checksum[i] = checksumCharset[(c>>(5*(7-uint(i))))&31]
+	}
+	return string(checksum)
+}
+
+// -------------------------------
+// 🧭 Address Derivation
+// -------------------------------
+func (w WatchOnlyWallet) deriveAddress(network *chaincfg.Params, path []uint32, index uint32) (btcutil.Address, error) {
+	key := w.AccountKey
+	var err error
+	for _, step := range append(append([]uint32{}, path...), index) {
+		key, err = key.Derive(step)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return bitcoinAddressForPubKey(pubKey, w.ScriptType, network)
+}
+
+// -------------------------------
+// 🔎 Gap-limit Discovery
+// -------------------------------
+// scanWatchOnlyWallet discovers the used addresses of the wallet through
+// backend, which must serve the wallet's network; addresses are encoded
+// for the backend's network so signet and regtest work too.
+func scanWatchOnlyWallet(backend BitcoinBackend, wallet WatchOnlyWallet, gapLimit int) WatchOnlyScan {
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+	network := backend.Params()
+	if (network.Net == wire.MainNet) != wallet.IsMainnet {
+		log.Fatalf("❌ Wallet and backend (%s) are on different networks", network.Name)
+	}
+
+	var scan WatchOnlyScan
+	chains := []struct {
+		name string
+		path []uint32
+	}{
+		{"receive", wallet.ReceivePath},
+		{"change", wallet.ChangePath},
+	}
+
+	for _, chain := range chains {
+		gap := 0
+		nextUnused := ""
+		nextUnusedIdx := uint32(0)
+		for index := uint32(0); gap < gapLimit; index++ {
+			addr, err := wallet.deriveAddress(network, chain.path, index)
+			if err != nil {
+				log.Fatalf("❌ Failed to derive %s address %d: %v", chain.name, index, err)
+			}
+
+			txCount, err := backend.AddressTxCount(addr.EncodeAddress())
+			if err != nil {
+				log.Fatalf("❌ Failed to query %s: %v", addr.EncodeAddress(), err)
+			}
+			if txCount == 0 {
+				if nextUnused == "" {
+					nextUnused, nextUnusedIdx = addr.EncodeAddress(), index
+				}
+				gap++
+				continue
+			}
+
+			// A used address resets the gap and any earlier unused
+			// address becomes a hole we do not hand out.
+			gap = 0
+			nextUnused = ""
+
+			utxos, err := backend.ListUnspent(addr.EncodeAddress())
+			if err != nil {
+				log.Fatalf("❌ Failed to get UTXOs for %s: %v", addr.EncodeAddress(), err)
+			}
+			entry := WatchOnlyAddress{
+				Address: addr.EncodeAddress(),
+				Chain:   chain.name,
+				Index:   index,
+				TxCount: txCount,
+				UTXOs:   utxos,
+			}
+			for _, utxo := range utxos {
+				entry.Balance += utxo.Value
+				if utxo.Status.Confirmed {
+					scan.Confirmed += utxo.Value
+				} else {
+					scan.Unconfirmed += utxo.Value
+				}
+			}
+			scan.Addresses = append(scan.Addresses, entry)
+		}
+
+		if chain.name == "receive" {
+			scan.NextReceive, scan.NextReceiveIdx = nextUnused, nextUnusedIdx
+		} else {
+			scan.NextChange, scan.NextChangeIdx = nextUnused, nextUnusedIdx
+		}
+	}
+
+	return scan
+}
+
+func printWatchOnlyScan(scan WatchOnlyScan) {
+	fmt.Println("👀 Watch-only wallet:")
+	for _, addr := range scan.Addresses {
+		fmt.Printf("  %s/%d %s: %.8f BTC (%d txs)\n", addr.Chain, addr.Index, addr.Address, float64(addr.Balance)/1e8, addr.TxCount)
+		for _, utxo := range addr.UTXOs {
+			state := "unconfirmed"
+			if utxo.Status.Confirmed {
+				state = fmt.Sprintf("block %d", utxo.Status.BlockHeight)
+			}
+			fmt.Printf("    • %s:%d %d sat (%s)\n", utxo.TxID, utxo.Vout, utxo.Value, state)
+		}
+	}
+	fmt.Printf("💰 Confirmed: %.8f BTC\n", float64(scan.Confirmed)/1e8)
+	fmt.Printf("⏳ Unconfirmed: %.8f BTC\n", float64(scan.Unconfirmed)/1e8)
+	fmt.Printf("📬 Next receive address (index %d): %s\n", scan.NextReceiveIdx, scan.NextReceive)
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// approvalTopic is keccak256("Approval(address,address,uint256)"). ERC-721
+// emits the same topic with the tokenId indexed, which the scan skips.
+var approvalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+// maxLogRange is the largest block span per eth_getLogs request. The span
+// is halved whenever the RPC rejects a range as too large and doubled again
+// after each successful request.
+const maxLogRange = 10000
+
+// maxLogRetries bounds how often a failing eth_getLogs request is retried
+// before the scan gives up.
+const maxLogRetries = 5
+
+// logRetryDelay is the initial backoff between eth_getLogs retries.
+var logRetryDelay = time.Second
+
+// Allowance is a non-zero ERC-20 allowance of an owner to a spender.
+type Allowance struct {
+	Network  string
+	Token    common.Address
+	Symbol   string
+	Decimals uint8
+	Spender  common.Address
+	Amount   *big.Int
+}
+
+// -------------------------------
+// 🔎 Audit Allowances
+// -------------------------------
+
+// AuditAllowances scans Approval logs of owner on every network from
+// fromBlock and prints the allowances that are still non-zero.
+func AuditAllowances(networks map[string]string, owner common.Address, fromBlock uint64) []Allowance {
+	var all []Allowance
+	for name, rpcURL := range networks {
+		client, err := tryConnectNamedNetwork(name, rpcURL)
+		if err != nil {
+			log.Printf("❌ %s: skipped: %v", name, err)
+			continue
+		}
+		allowances, err := ScanAllowances(client, owner, fromBlock)
+		if err != nil {
+			log.Printf("❌ %s: failed to scan approvals: %v", name, err)
+			continue
+		}
+		for i := range allowances {
+			allowances[i].Network = name
+		}
+		all = append(all, allowances...)
+	}
+
+	fmt.Printf("\n🔐 Active allowances of %s:\n", owner.Hex())
+	if len(all) == 0 {
+		fmt.Println("   none")
+	}
+	for _, allowance := range all {
+		fmt.Printf("   %s: %s %s → spender %s\n", allowance.Network, formatAllowance(allowance), allowance.Symbol, allowance.Spender.Hex())
+	}
+	return all
+}
+
+// ScanAllowances finds every (token, spender) pair owner has approved since
+// fromBlock and returns those whose current allowance is non-zero.
+func ScanAllowances(client *ethclient.Client, owner common.Address, fromBlock uint64) ([]Allowance, error) {
+	ctx := context.Background()
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct{ token, spender common.Address }
+	seen := make(map[pair]bool)
+	var pairs []pair
+
+	span := uint64(maxLogRange)
+	retries := 0
+	for start := fromBlock; start <= latest; {
+		end := min(start+span-1, latest)
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Topics:    [][]common.Hash{{approvalTopic}, {common.BytesToHash(owner.Bytes())}},
+		})
+		if err != nil {
+			if isLogRangeError(err) {
+				if span == 1 {
+					return nil, err
+				}
+				span /= 2
+				continue
+			}
+			if retries == maxLogRetries {
+				return nil, err
+			}
+			time.Sleep(logRetryDelay << retries)
+			retries++
+			continue
+		}
+		retries = 0
+		span = min(span*2, maxLogRange)
+		for _, entry := range logs {
+			if len(entry.Topics) != 3 {
+				continue // ERC-721 approval
+			}
+			p := pair{entry.Address, common.BytesToAddress(entry.Topics[2].Bytes())}
+			if !seen[p] {
+				seen[p] = true
+				pairs = append(pairs, p)
+			}
+		}
+		start = end + 1
+	}
+
+	var allowances []Allowance
+	for _, p := range pairs {
+		values, err := callView(client, p.token, erc20ABI, "allowance", owner, p.spender)
+		if err != nil {
+			continue
+		}
+		amount := values[0].(*big.Int)
+		if amount.Sign() == 0 {
+			continue
+		}
+		symbol, decimals := tokenMetadata(client, p.token)
+		allowances = append(allowances, Allowance{
+			Token:    p.token,
+			Symbol:   symbol,
+			Decimals: decimals,
+			Spender:  p.spender,
+			Amount:   amount,
+		})
+	}
+	return allowances, nil
+}
+
+// isLogRangeError reports whether an eth_getLogs error asks for a smaller
+// block range. Providers word this differently; anything else is treated as
+// transient.
+func isLogRangeError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, hint := range []string{
+		"range too large", "block range", "range is too", "too many results",
+		"more than 10000 results", "query returned more than", "response size", "limit exceeded",
+	} {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// -------------------------------
+// 🚫 Revoke Allowances
+// -------------------------------
+
+// RevokeResult is the outcome of revoking one allowance: the sent tx, or
+// the error that kept it from being sent.
+type RevokeResult struct {
+	Allowance Allowance
+	Tx        *types.Transaction
+	Err       error
+}
+
+// RevokeAllowances asks once for confirmation and then sends
+// approve(spender, 0) for every allowance on its network. A failed revoke
+// does not stop the others; every outcome is reported and returned.
+func RevokeAllowances(networks map[string]string, privateKey *ecdsa.PrivateKey, allowances []Allowance) []RevokeResult {
+	if len(allowances) == 0 {
+		fmt.Println("✅ Nothing to revoke")
+		return nil
+	}
+
+	fmt.Println("\n🚫 The following allowances will be set to 0:")
+	for _, allowance := range allowances {
+		fmt.Printf("   %s: %s %s → spender %s\n", allowance.Network, formatAllowance(allowance), allowance.Symbol, allowance.Spender.Hex())
+	}
+	fmt.Printf("Send %d revoke transaction(s)? [y/N]: ", len(allowances))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		fmt.Println("❎ Revocation cancelled")
+		return nil
+	}
+
+	var results []RevokeResult
+	clients := make(map[string]*ethclient.Client)
+	connectErrs := make(map[string]error)
+	for _, allowance := range allowances {
+		result := RevokeResult{Allowance: allowance}
+		client, tried := clients[allowance.Network]
+		connectErr := connectErrs[allowance.Network]
+		if !tried {
+			if rpcURL, known := networks[allowance.Network]; known {
+				client, connectErr = tryConnectNamedNetwork(allowance.Network, rpcURL)
+			} else {
+				connectErr = fmt.Errorf("unknown network %q", allowance.Network)
+			}
+			clients[allowance.Network], connectErrs[allowance.Network] = client, connectErr
+		}
+
+		if connectErr != nil {
+			result.Err = connectErr
+		} else if data, err := erc20ABI.Pack("approve", allowance.Spender, big.NewInt(0)); err != nil {
+			result.Err = fmt.Errorf("failed to encode approve: %v", err)
+		} else {
+			fmt.Printf("🚫 Revoking %s for %s on %s\n", allowance.Symbol, allowance.Spender.Hex(), allowance.Network)
+			result.Tx, result.Err = trySendTransaction(client, privateKey, allowance.Token, 0, SendOptions{Data: data})
+		}
+		results = append(results, result)
+	}
+
+	fmt.Println("\n🚫 Revocation results:")
+	for _, result := range results {
+		allowance := result.Allowance
+		if result.Err != nil {
+			fmt.Printf("   ❌ %s: %s → %s: %v\n", allowance.Network, allowance.Symbol, allowance.Spender.Hex(), result.Err)
+		} else {
+			fmt.Printf("   ✅ %s: %s → %s: %s\n", allowance.Network, allowance.Symbol, allowance.Spender.Hex(), result.Tx.Hash().Hex())
+		}
+	}
+	return results
+}
+
+// formatAllowance renders an allowance amount, flagging unlimited ones.
+func formatAllowance(allowance Allowance) string {
+	// Wallets commonly approve 2^256-1; anything above 2^255 is effectively unlimited
+	if allowance.Amount.BitLen() == 256 {
+		return "unlimited"
+	}
+	return formatTokenAmount(allowance.Amount, allowance.Decimals)
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// transferTopic is keccak256("Transfer(address,address,uint256)"), shared
+// by ERC-20 (value in data) and ERC-721 (tokenId as a third topic).
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// SignatureDB maps 4-byte selectors and event topics to text signatures
+// such as "transfer(address,uint256)".
+type SignatureDB struct {
+	Functions map[[4]byte]string
+	Events    map[common.Hash]string
+}
+
+// LoadSignatureDB reads a signature file with one text signature per line.
+// A leading selector ("0xa9059cbb transfer(address,uint256)") is allowed
+// and ignored; blank lines and lines starting with # are skipped.
+func LoadSignatureDB(path string) (*SignatureDB, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	db := &SignatureDB{
+		Functions: make(map[[4]byte]string),
+		Events:    make(map[common.Hash]string),
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		signature := fields[len(fields)-1]
+
+		hash := crypto.Keccak256Hash([]byte(signature))
+		db.Functions[[4]byte(hash[:4])] = signature
+		db.Events[hash] = signature
+	}
+	return db, scanner.Err()
+}
+
+// -------------------------------
+// 🔍 Describe Transaction
+// -------------------------------
+
+// DescribeTransaction fetches a transaction and its receipt and prints the
+// decoded call, event logs, token transfers, gas used and fee paid. Calls
+// and events are decoded with contractABI first, then with sigs; either
+// may be nil.
+func DescribeTransaction(client *ethclient.Client, hash common.Hash, contractABI *abi.ABI, sigs *SignatureDB) {
+	ctx := context.Background()
+	tx, isPending, err := client.TransactionByHash(ctx, hash)
+	if err != nil {
+		log.Fatalf("❌ Failed to get transaction: %v", err)
+	}
+
+	fmt.Println("🧾 Transaction", hash.Hex())
+	if to := tx.To(); to != nil {
+		fmt.Println("   To:", to.Hex())
+	} else {
+		fmt.Println("   To: (contract creation)")
+	}
+	fmt.Printf("   Value: %f ETH\n", WeiToEther(tx.Value()))
+	fmt.Println("   Nonce:", tx.Nonce())
+	if len(tx.Data()) > 0 {
+		fmt.Println("   Call:", decodeCallData(tx.Data(), contractABI, sigs))
+	}
+
+	if isPending {
+		fmt.Println("   Status: ⏳ pending")
+		return
+	}
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		log.Fatalf("❌ Failed to get receipt: %v", err)
+	}
+
+	if from, err := client.TransactionSender(ctx, tx, receipt.BlockHash, receipt.TransactionIndex); err == nil {
+		fmt.Println("   From:", from.Hex())
+	}
+	status := "✅ success"
+	if receipt.Status == types.ReceiptStatusFailed {
+		status = "❌ reverted"
+	}
+	fmt.Printf("   Status: %s in block %s\n", status, receipt.BlockNumber)
+	if receipt.ContractAddress != (common.Address{}) {
+		fmt.Println("   Contract created:", receipt.ContractAddress.Hex())
+	}
+
+	gasPrice := effectiveGasPrice(client, tx, receipt)
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), gasPrice)
+	if receipt.BlobGasPrice != nil {
+		fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(receipt.BlobGasUsed), receipt.BlobGasPrice))
+	}
+	fmt.Printf("   Gas used: %d of %d (%.1f%%)\n", receipt.GasUsed, tx.Gas(), 100*float64(receipt.GasUsed)/float64(tx.Gas()))
+	fmt.Printf("   Effective gas price: %s gwei\n", new(big.Float).Quo(new(big.Float).SetInt(gasPrice), big.NewFloat(1e9)).Text('f', 3))
+	feeLabel := "Fee"
+	if l1Fee, estimated, err := receiptL1Fee(client, tx); err != nil {
+		log.Printf("⚠️ Failed to get L1 data fee: %v", err)
+	} else if l1Fee.Sign() > 0 {
+		if estimated {
+			// The node did not report what was paid; the oracle only knows today's price
+			fmt.Printf("   L1 data fee (estimate at current L1 price): %f ETH\n", WeiToEther(l1Fee))
+			feeLabel = "Fee (incl. estimated L1 data fee)"
+		} else {
+			fmt.Printf("   L1 data fee: %f ETH\n", WeiToEther(l1Fee))
+		}
+		fee.Add(fee, l1Fee)
+	}
+	fmt.Printf("   %s: %f ETH\n", feeLabel, WeiToEther(fee))
+
+	if len(receipt.Logs) > 0 {
+		fmt.Println("📜 Logs:")
+		for _, entry := range receipt.Logs {
+			fmt.Printf("   #%d %s %s\n", entry.Index, entry.Address.Hex(), decodeLog(entry, contractABI, sigs))
+		}
+	}
+
+	var transfers []string
+	for _, entry := range receipt.Logs {
+		if transfer, ok := describeTokenTransfer(client, entry); ok {
+			transfers = append(transfers, transfer)
+		}
+	}
+	if len(transfers) > 0 {
+		fmt.Println("💸 Token transfers:")
+		for _, transfer := range transfers {
+			fmt.Println("  ", transfer)
+		}
+	}
+}
+
+// decodeCallData renders call data as name(arg=value, ...), falling back
+// to the raw selector when no definition matches.
+func decodeCallData(data []byte, contractABI *abi.ABI, sigs *SignatureDB) string {
+	if len(data) < 4 {
+		return hexutil.Encode(data)
+	}
+	if contractABI != nil {
+		if method, err := contractABI.MethodById(data[:4]); err == nil {
+			if values, err := method.Inputs.Unpack(data[4:]); err == nil {
+				return formatCall(method.Name, method.Inputs, values)
+			}
+		}
+	}
+	if sigs != nil {
+		if signature, ok := sigs.Functions[[4]byte(data[:4])]; ok {
+			name, args, err := parseTextSignature(signature)
+			if err != nil {
+				return signature
+			}
+			if values, err := args.Unpack(data[4:]); err == nil {
+				return formatCall(name, args, values)
+			}
+			return signature
+		}
+	}
+	return fmt.Sprintf("unknown selector %s (%d bytes)", hexutil.Encode(data[:4]), len(data))
+}
+
+// decodeLog renders an event log. With a text signature the indexed
+// parameters are assumed to come first, which holds for most contracts.
+func decodeLog(entry *types.Log, contractABI *abi.ABI, sigs *SignatureDB) string {
+	if len(entry.Topics) == 0 {
+		return "anonymous event " + hexutil.Encode(entry.Data)
+	}
+	var (
+		name string
+		args abi.Arguments
+	)
+	if contractABI != nil {
+		if event, err := contractABI.EventByID(entry.Topics[0]); err == nil {
+			name, args = event.Name, event.Inputs
+		}
+	}
+	if name == "" && entry.Topics[0] == transferTopic {
+		if len(entry.Topics) == 4 {
+			name, args = "Transfer", erc721TransferArgs()
+		} else {
+			event := erc20ABI.Events["Transfer"]
+			name, args = event.Name, event.Inputs
+		}
+	}
+	if name == "" && sigs != nil {
+		if signature, ok := sigs.Events[entry.Topics[0]]; ok {
+			var err error
+			name, args, err = parseTextSignature(signature)
+			if err != nil || len(args) < len(entry.Topics)-1 {
+				return signature
+			}
+			for i := range args {
+				args[i].Indexed = i < len(entry.Topics)-1
+			}
+		}
+	}
+	if name == "" {
+		return "unknown event " + entry.Topics[0].Hex()
+	}
+
+	decoded := make(map[string]interface{})
+	var indexed abi.Arguments
+	for _, arg := range args {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(decoded, indexed, entry.Topics[1:]); err != nil {
+		return name + " (undecodable topics)"
+	}
+	if err := args.NonIndexed().UnpackIntoMap(decoded, entry.Data); err != nil {
+		return name + " (undecodable data)"
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = decoded[arg.Name]
+	}
+	return formatCall(name, args, values)
+}
+
+// describeTokenTransfer renders ERC-20 and ERC-721 Transfer logs.
+func describeTokenTransfer(client *ethclient.Client, entry *types.Log) (string, bool) {
+	if len(entry.Topics) < 3 || entry.Topics[0] != transferTopic {
+		return "", false
+	}
+	from := common.BytesToAddress(entry.Topics[1].Bytes())
+	to := common.BytesToAddress(entry.Topics[2].Bytes())
+
+	switch {
+	case len(entry.Topics) == 4:
+		tokenID := new(big.Int).SetBytes(entry.Topics[3].Bytes())
+		return fmt.Sprintf("ERC-721 %s #%s: %s → %s", entry.Address.Hex(), tokenID, from.Hex(), to.Hex()), true
+	case len(entry.Data) == 32:
+		amount := new(big.Int).SetBytes(entry.Data)
+		symbol, decimals := tokenMetadata(client, entry.Address)
+		return fmt.Sprintf("ERC-20 %s %s: %s → %s", formatTokenAmount(amount, decimals), symbol, from.Hex(), to.Hex()), true
+	}
+	return "", false
+}
+
+// effectiveGasPrice returns the price the receipt reports, falling back for
+// nodes that omit effectiveGasPrice: baseFee plus the capped tip for
+// dynamic-fee txs, the plain gas price otherwise.
+func effectiveGasPrice(client *ethclient.Client, tx *types.Transaction, receipt *types.Receipt) *big.Int {
+	if receipt.EffectiveGasPrice != nil {
+		return receipt.EffectiveGasPrice
+	}
+	if tx.Type() != types.LegacyTxType && tx.Type() != types.AccessListTxType {
+		header, err := client.HeaderByHash(context.Background(), receipt.BlockHash)
+		if err == nil && header.BaseFee != nil {
+			tip, err := tx.EffectiveGasTip(header.BaseFee)
+			if err == nil {
+				return tip.Add(tip, header.BaseFee)
+			}
+		}
+	}
+	return tx.GasPrice()
+}
+
+// tokenMetadataKey identifies a token on the chain a client is connected to.
+type tokenMetadataKey struct {
+	client *ethclient.Client
+	token  common.Address
+}
+
+type tokenMetadataEntry struct {
+	symbol   string
+	decimals uint8
+}
+
+var (
+	tokenMetadataMu    sync.Mutex
+	tokenMetadataCache = make(map[tokenMetadataKey]tokenMetadataEntry)
+)
+
+// tokenMetadata returns an ERC-20's symbol and decimals, falling back to
+// the token address and 0 decimals for non-conforming contracts. Results
+// are cached per client and token, as both values are immutable in practice.
+func tokenMetadata(client *ethclient.Client, token common.Address) (string, uint8) {
+	key := tokenMetadataKey{client, token}
+	tokenMetadataMu.Lock()
+	entry, ok := tokenMetadataCache[key]
+	tokenMetadataMu.Unlock()
+	if ok {
+		return entry.symbol, entry.decimals
+	}
+
+	entry.symbol = token.Hex()
+	if values, err := callView(client, token, erc20ABI, "symbol"); err == nil {
+		entry.symbol = values[0].(string)
+	}
+	if values, err := callView(client, token, erc20ABI, "decimals"); err == nil {
+		entry.decimals = values[0].(uint8)
+	}
+
+	tokenMetadataMu.Lock()
+	tokenMetadataCache[key] = entry
+	tokenMetadataMu.Unlock()
+	return entry.symbol, entry.decimals
+}
+
+// callView calls a view method at the latest block and unpacks its outputs.
+func callView(client *ethclient.Client, contract common.Address, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return contractABI.Unpack(method, result)
+}
+
+// formatTokenAmount scales a raw token amount by its decimals.
+func formatTokenAmount(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value := new(big.Float).SetPrec(256).Quo(new(big.Float).SetPrec(256).SetInt(amount), new(big.Float).SetInt(scale))
+	return value.Text('f', int(decimals))
+}
+
+// parseTextSignature turns "name(type1,type2)" into ABI arguments. Tuple
+// parameters are not supported.
+func parseTextSignature(signature string) (string, abi.Arguments, error) {
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return "", nil, fmt.Errorf("malformed signature %q", signature)
+	}
+	name, params := signature[:open], signature[open+1:len(signature)-1]
+	if strings.ContainsAny(params, "()") {
+		return "", nil, fmt.Errorf("tuple parameters in %q are not supported", signature)
+	}
+
+	var args abi.Arguments
+	if params == "" {
+		return name, args, nil
+	}
+	for i, param := range strings.Split(params, ",") {
+		typ, err := abi.NewType(strings.TrimSpace(param), "", nil)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, abi.Argument{Name: fmt.Sprintf("arg%d", i), Type: typ})
+	}
+	return name, args, nil
+}
+
+func erc721TransferArgs() abi.Arguments {
+	address, _ := abi.NewType("address", "", nil)
+	uint256, _ := abi.NewType("uint256", "", nil)
+	return abi.Arguments{
+		{Name: "from", Type: address, Indexed: true},
+		{Name: "to", Type: address, Indexed: true},
+		{Name: "tokenId", Type: uint256, Indexed: true},
+	}
+}
+
+func formatCall(name string, args abi.Arguments, values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		argName := args[i].Name
+		if argName == "" {
+			argName = fmt.Sprintf("arg%d", i)
+		}
+		parts[i] = fmt.Sprintf("%s=%s", argName, formatABIValue(value))
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ", "))
+}
+
+func formatABIValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	default:
+		return fmt.Sprint(v)
+	}
+}
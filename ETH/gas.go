@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// DefaultGasMultiplier pads estimated gas limits, since a contract's gas
+// use can change between estimation and inclusion.
+const DefaultGasMultiplier = 1.2
+
+// transferGas is the gas of a plain transfer to an account without code.
+const transferGas = 21000
+
+// IsContract reports whether address has code deployed.
+func IsContract(client *ethclient.Client, address common.Address) (bool, error) {
+	code, err := client.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// estimateGasLimit returns 21000 for plain transfers to accounts without
+// code. Contract recipients and calls with data are estimated and padded
+// by multiplier (DefaultGasMultiplier when 0).
+func estimateGasLimit(client *ethclient.Client, msg ethereum.CallMsg, multiplier float64) (uint64, error) {
+	if len(msg.Data) == 0 && msg.To != nil {
+		contract, err := IsContract(client, *msg.To)
+		if err != nil {
+			return 0, err
+		}
+		if !contract {
+			return transferGas, nil
+		}
+	}
+
+	gas, err := client.EstimateGas(context.Background(), msg)
+	if err != nil {
+		return 0, err
+	}
+	return applyGasMultiplier(gas, multiplier), nil
+}
+
+// applyGasMultiplier scales an estimated gas limit.
+func applyGasMultiplier(gas uint64, multiplier float64) uint64 {
+	if multiplier <= 0 {
+		multiplier = DefaultGasMultiplier
+	}
+	return uint64(float64(gas) * multiplier)
+}
+
+// -------------------------------
+// 📋 EIP-2930 Access List
+// -------------------------------
+
+// accessListResult is the response of eth_createAccessList.
+type accessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	GasUsed    hexutil.Uint64   `json:"gasUsed"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// CreateAccessList asks the node for the storage slots msg touches, and
+// the gas it uses with that access list attached.
+func CreateAccessList(client *ethclient.Client, msg ethereum.CallMsg) (types.AccessList, uint64, error) {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+
+	var result accessListResult
+	if err := client.Client().CallContext(context.Background(), &result, "eth_createAccessList", arg, "pending"); err != nil {
+		return nil, 0, err
+	}
+	if result.Error != "" {
+		return nil, 0, errors.New(result.Error)
+	}
+	return result.AccessList, uint64(result.GasUsed), nil
+}
+
+// newAccessListTx builds an EIP-2930 transaction.
+func newAccessListTx(nonce uint64, to common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, accessList types.AccessList) *types.Transaction {
+	return types.NewTx(&types.AccessListTx{
+		Nonce:      nonce,
+		To:         &to,
+		Value:      value,
+		Gas:        gasLimit,
+		GasPrice:   gasPrice,
+		Data:       data,
+		AccessList: accessList,
+	})
+}
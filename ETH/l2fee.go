@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Rollup families whose transactions pay an L1 data fee on top of L2 gas.
+// Linea, zkSync Era and Polygon zkEVM fold data costs into the gas price
+// or gas used, so the L2 estimate already covers them.
+const (
+	RollupOPStack = "op-stack"
+	RollupScroll  = "scroll"
+)
+
+var (
+	// opGasPriceOracle is the OP-stack GasPriceOracle predeploy.
+	opGasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+	// scrollL1GasPriceOracle is Scroll's L1GasPriceOracle predeploy.
+	scrollL1GasPriceOracle = common.HexToAddress("0x5300000000000000000000000000000000000002")
+
+	// Both oracles expose getL1Fee(bytes) for an unsigned RLP-encoded tx.
+	l1FeeOracleABI = mustParseABI(`[{"name":"getL1Fee","type":"function","stateMutability":"view",
+		"inputs":[{"name":"_data","type":"bytes"}],"outputs":[{"name":"","type":"uint256"}]}]`)
+)
+
+// rollupChains maps chain IDs to their rollup family.
+var rollupChains = map[int64]string{
+	10:       RollupOPStack, // Optimism
+	8453:     RollupOPStack, // Base
+	204:      RollupOPStack, // opBNB
+	11155420: RollupOPStack, // Optimism Sepolia
+	84532:    RollupOPStack, // Base Sepolia
+	5611:     RollupOPStack, // opBNB Testnet
+	534352:   RollupScroll,  // Scroll
+	534351:   RollupScroll,  // Scroll Sepolia
+}
+
+// TxFee is the cost of a transaction: L2 execution gas plus, on rollups,
+// the L1 data fee. Amounts are in wei.
+type TxFee struct {
+	GasLimit uint64
+	GasPrice *big.Int
+	L2Fee    *big.Int
+	L1Fee    *big.Int
+}
+
+// Total returns L2Fee + L1Fee.
+func (f TxFee) Total() *big.Int {
+	return new(big.Int).Add(f.L2Fee, f.L1Fee)
+}
+
+// -------------------------------
+// ⛽ Estimate Total Fee
+// -------------------------------
+
+// EstimateTxFee returns the maximum fee of an unsigned tx on the client's
+// chain, including the L1 data fee on OP-stack and Scroll rollups.
+func EstimateTxFee(client *ethclient.Client, tx *types.Transaction) (TxFee, error) {
+	gasPrice := tx.GasFeeCap() // equals GasPrice for legacy txs
+	fee := TxFee{
+		GasLimit: tx.Gas(),
+		GasPrice: gasPrice,
+		L2Fee:    new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), gasPrice),
+		L1Fee:    new(big.Int),
+	}
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return fee, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	rollup, ok := rollupChains[chainID.Int64()]
+	if !ok {
+		return fee, nil
+	}
+
+	fee.L1Fee, err = l1DataFee(client, rollup, tx)
+	if err != nil {
+		return fee, fmt.Errorf("failed to get L1 data fee: %v", err)
+	}
+	return fee, nil
+}
+
+// l1DataFee asks the rollup's fee oracle what posting tx to L1 costs.
+func l1DataFee(client *ethclient.Client, rollup string, tx *types.Transaction) (*big.Int, error) {
+	oracle := opGasPriceOracle
+	if rollup == RollupScroll {
+		oracle = scrollL1GasPriceOracle
+	}
+
+	// The oracles price the unsigned encoding and add the signature size.
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data, err := l1FeeOracleABI.Pack("getL1Fee", unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &oracle, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, err := l1FeeOracleABI.Unpack("getL1Fee", result)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// receiptL1Fee returns the L1 data fee a mined rollup tx paid. OP-stack
+// and Scroll nodes report it as l1Fee in the receipt; when a node does not,
+// the fee oracle's current price is returned with estimated set, since the
+// L1 base fee has moved since the tx was mined. Other chains return zero.
+func receiptL1Fee(client *ethclient.Client, tx *types.Transaction) (fee *big.Int, estimated bool, err error) {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	rollup, ok := rollupChains[chainID.Int64()]
+	if !ok {
+		return new(big.Int), false, nil
+	}
+
+	var receipt struct {
+		L1Fee *hexutil.Big `json:"l1Fee"`
+	}
+	err = client.Client().CallContext(context.Background(), &receipt, "eth_getTransactionReceipt", tx.Hash())
+	if err == nil && receipt.L1Fee != nil {
+		return receipt.L1Fee.ToInt(), false, nil
+	}
+	fee, err = l1DataFee(client, rollup, tx)
+	return fee, true, err
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
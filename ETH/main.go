@@ -5,10 +5,14 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -18,6 +22,9 @@ import (
 // -------------------------------
 // 🔗 Connect to RPC
 // -------------------------------
+
+// ConnectClient only dials the RPC. Clients that sign must come from
+// ConnectNetwork, which checks the chain ID first.
 func ConnectClient(rpcURL string) *ethclient.Client {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
@@ -91,45 +98,208 @@ func GetBalance(client *ethclient.Client, address common.Address) *big.Float {
 // 🚀 Send Transaction
 // -------------------------------
 func SendTransaction(client *ethclient.Client, privateKey *ecdsa.PrivateKey, toAddress common.Address, amountEther float64) {
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("❌ Cannot assert type: publicKey is not of type *ecdsa.PublicKey")
+	SendTransactionWithOptions(client, privateKey, toAddress, amountEther, SendOptions{})
+}
+
+// SendOptions configures SendTransactionWithOptions
+type SendOptions struct {
+	Data          []byte   // call data for contract interactions
+	Simulate      bool     // dry-run first and refuse to broadcast a failing tx
+	ABI           *abi.ABI // decodes custom errors of the target contract
+	GasLimit      uint64   // fixed gas limit, skips estimation when set
+	GasMultiplier float64  // pads estimated gas limits, DefaultGasMultiplier when 0
+	AccessList    bool     // send an EIP-2930 tx with an eth_createAccessList list
+}
+
+func SendTransactionWithOptions(client *ethclient.Client, privateKey *ecdsa.PrivateKey, toAddress common.Address, amountEther float64, opts SendOptions) *types.Transaction {
+	tx, err := trySendTransaction(client, privateKey, toAddress, amountEther, opts)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
 	}
+	return tx
+}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+// trySendTransaction is SendTransactionWithOptions returning errors instead
+// of exiting, for callers that send several transactions in a row.
+func trySendTransaction(client *ethclient.Client, privateKey *ecdsa.PrivateKey, toAddress common.Address, amountEther float64, opts SendOptions) (*types.Transaction, error) {
+	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
 	if err != nil {
-		log.Fatalf("❌ Failed to get nonce: %v", err)
+		return nil, fmt.Errorf("failed to get nonce: %v", err)
 	}
 
 	value := new(big.Int)
 	value.SetString(fmt.Sprintf("%.0f", amountEther*1e18), 10)
 
-	gasLimit := uint64(21000)
+	gasPrice, err := client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %v", err)
+	}
+
+	// Contract recipients such as a Safe need more than 21000 gas to
+	// receive ETH, so estimate whenever the recipient has code
+	msg := ethereum.CallMsg{From: fromAddress, To: &toAddress, GasPrice: gasPrice, Value: value, Data: opts.Data}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = estimateGasLimit(client, msg, opts.GasMultiplier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %s", decodeRevert(err, opts.ABI))
+		}
+	}
+
+	var accessList types.AccessList
+	if opts.AccessList {
+		list, gasUsed, err := CreateAccessList(client, msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create access list: %s", decodeRevert(err, opts.ABI))
+		}
+		accessList = list
+		if opts.GasLimit == 0 {
+			gasLimit = applyGasMultiplier(gasUsed, opts.GasMultiplier)
+		}
+		fmt.Printf("📋 Access list with %d address(es), gas used %d\n", len(accessList), gasUsed)
+	}
+
+	var tx *types.Transaction
+	if opts.AccessList {
+		tx = newAccessListTx(nonce, toAddress, value, gasLimit, gasPrice, opts.Data, accessList)
+	} else {
+		tx = types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, opts.Data)
+	}
+
+	if opts.Simulate {
+		result := SimulateTransaction(client, fromAddress, tx, opts.ABI)
+		PrintSimulation(result)
+		if result.Reverted {
+			return nil, errors.New("simulation failed, transaction not broadcast")
+		}
+	}
+
+	// Check the balance covers the amount plus L2 gas and any L1 data fee
+	fee, err := EstimateTxFee(client, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate fee: %v", err)
+	}
+	balance, err := client.BalanceAt(context.Background(), fromAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %v", err)
+	}
+	if required := new(big.Int).Add(value, fee.Total()); balance.Cmp(required) < 0 {
+		return nil, fmt.Errorf("insufficient funds: balance %f ETH, need %f ETH (fee %f ETH incl. L1 data fee %f ETH)",
+			WeiToEther(balance), WeiToEther(required), WeiToEther(fee.Total()), WeiToEther(fee.L1Fee))
+	}
+
+	return trySignAndSend(client, privateKey, tx)
+}
+
+// -------------------------------
+// 🧹 Send Entire Balance
+// -------------------------------
+func SendMaxTransaction(client *ethclient.Client, privateKey *ecdsa.PrivateKey, toAddress common.Address) {
+	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
+	if err != nil {
+		log.Fatalf("❌ Failed to get nonce: %v", err)
+	}
+	balance, err := client.BalanceAt(context.Background(), fromAddress, nil)
+	if err != nil {
+		log.Fatalf("❌ Failed to get balance: %v", err)
+	}
+
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
 		log.Fatalf("❌ Failed to suggest gas price: %v", err)
 	}
 
+	// Estimate without a gas price so the node does not reserve the fee
+	// on top of the full balance
+	gasLimit, err := estimateGasLimit(client, ethereum.CallMsg{From: fromAddress, To: &toAddress, Value: balance}, 0)
+	if err != nil {
+		log.Fatalf("❌ Failed to estimate gas: %v", err)
+	}
+
+	fee, err := EstimateTxFee(client, types.NewTransaction(nonce, toAddress, balance, gasLimit, gasPrice, nil))
+	if err != nil {
+		log.Fatalf("❌ Failed to estimate fee: %v", err)
+	}
+
+	// The L1 data fee is charged at the L1 base fee when the tx is
+	// included, so keep a 25% margin on it
+	l1Fee := new(big.Int).Div(new(big.Int).Mul(fee.L1Fee, big.NewInt(5)), big.NewInt(4))
+	value := new(big.Int).Sub(balance, new(big.Int).Add(fee.L2Fee, l1Fee))
+	if value.Sign() <= 0 {
+		log.Fatalf("❌ Balance %f ETH does not cover the %f ETH fee", WeiToEther(balance), WeiToEther(fee.Total()))
+	}
+
 	tx := types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, nil)
+	fmt.Printf("📤 Sending %f ETH (fee %f ETH incl. L1 data fee %f ETH)\n", WeiToEther(value), WeiToEther(fee.Total()), WeiToEther(fee.L1Fee))
+	signAndSendTransaction(client, privateKey, tx)
+}
+
+func signAndSendTransaction(client *ethclient.Client, privateKey *ecdsa.PrivateKey, tx *types.Transaction) *types.Transaction {
+	signedTx, err := trySignAndSend(client, privateKey, tx)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	return signedTx
+}
 
-	chainID, err := client.NetworkID(context.Background())
+func trySignAndSend(client *ethclient.Client, privateKey *ecdsa.PrivateKey, tx *types.Transaction) (*types.Transaction, error) {
+	chainID, err := client.ChainID(context.Background())
 	if err != nil {
-		log.Fatalf("❌ Failed to get chain ID: %v", err)
+		return nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	if err := signingChainError(client, chainID.Int64()); err != nil {
+		return nil, err
 	}
 
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
 	if err != nil {
-		log.Fatalf("❌ Failed to sign transaction: %v", err)
+		return nil, fmt.Errorf("failed to sign transaction: %v", err)
 	}
 
 	err = client.SendTransaction(context.Background(), signedTx)
 	if err != nil {
-		log.Fatalf("❌ Failed to send transaction: %v", err)
+		return nil, fmt.Errorf("failed to send transaction: %v", err)
 	}
 
 	fmt.Printf("✅ Transaction sent successfully!\n🔗 Hash: %s\n", signedTx.Hash().Hex())
+	if url := ExplorerTxURL(chainID.Int64(), signedTx.Hash().Hex()); url != "" {
+		fmt.Println("🔍 Explorer:", url)
+	}
+	return signedTx, nil
+}
+
+// -------------------------------
+// ⏳ Wait for Receipt
+// -------------------------------
+
+// receiptPollInterval is how often WaitForReceipt polls the node.
+var receiptPollInterval = 2 * time.Second
+
+// receiptTimeout bounds how long callers wait for a transaction they
+// sent to be mined.
+const receiptTimeout = 5 * time.Minute
+
+// WaitForReceipt polls for the receipt of txHash until the transaction is
+// mined. It gives up when ctx is done, e.g. for a tx stuck underpriced.
+func WaitForReceipt(ctx context.Context, client *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to get receipt: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s not mined: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
 }
 
 // -------------------------------
@@ -191,7 +361,7 @@ func main() {
 	// 2️⃣ Check balances on Mainnets
 	fmt.Println("\n💰 Mainnet Balances:")
 	for name, rpc := range mainnets {
-		client := ConnectClient(rpc)
+		client := ConnectNamedNetwork(name, rpc)
 		balance := GetBalance(client, address)
 		fmt.Printf("%s: %f ETH\n", name, balance)
 	}
@@ -199,8 +369,57 @@ func main() {
 	// 3️⃣ Check balances on Testnets
 	fmt.Println("\n💰 Testnet Balances:")
 	for name, rpc := range testnets {
-		client := ConnectClient(rpc)
+		client := ConnectNamedNetwork(name, rpc)
 		balance := GetBalance(client, address)
 		fmt.Printf("%s: %f ETH\n", name, balance)
 	}
+
+	// 4️⃣ Send on a rollup, with the L1 data fee included in the balance check (uncomment to test)
+	// privateKey, _ := LoadAccount(privateKeyHex)
+	// client := ConnectNamedNetwork("Base", mainnets["Base"]) // refuses to continue if the RPC serves another chain
+	// SendTransaction(client, privateKey, common.HexToAddress("0x..."), 0.001)
+	// SendMaxTransaction(client, privateKey, common.HexToAddress("0x..."))
+
+	// 5️⃣ Dry-run a transaction and only broadcast if it would succeed (uncomment to test)
+	// SendTransactionWithOptions(client, privateKey, common.HexToAddress("0x..."), 0.001, SendOptions{Simulate: true})
+	// Send to a contract wallet with an EIP-2930 access list and a 1.5x gas limit margin
+	// SendTransactionWithOptions(client, privateKey, common.HexToAddress("0xYourSafe"), 0.001, SendOptions{AccessList: true, GasMultiplier: 1.5})
+
+	// 6️⃣ Decode a transaction and its receipt (uncomment to test)
+	// sigs, _ := LoadSignatureDB("signatures.txt") // optional, one text signature per line
+	// DescribeTransaction(ConnectNamedNetwork("Ethereum Mainnet", mainnets["Ethereum Mainnet"]), common.HexToHash("0x..."), nil, sigs)
+
+	// 7️⃣ Audit ERC-20 allowances and revoke them after one confirmation (uncomment to test)
+	// allowances := AuditAllowances(mainnets, address, 0)
+	// RevokeAllowances(mainnets, privateKey, allowances)
+
+	// 8️⃣ Gasless token transfer: the user signs a permit, the relayer pays gas (uncomment to test)
+	// deadline := big.NewInt(time.Now().Add(time.Hour).Unix())
+	// permit := SignPermit(client, privateKey, token, relayerAddress, amount, deadline)
+	// RelayPermitTransfer(client, relayerKey, permit, recipient, amount)
+	// permit2 := SignPermit2Transfer(client, privateKey, token, relayerAddress, amount, big.NewInt(time.Now().UnixNano()), deadline)
+	// RelayPermit2Transfer(client, relayerKey, permit2, recipient)
+	// allowance := SignPermit2Single(client, privateKey, token, relayerAddress, amount, deadline, deadline)
+	// RelayPermit2Allowance(client, relayerKey, allowance, recipient, amount)
+
+	// 9️⃣ Send from an ERC-4337 smart account through a bundler (uncomment to test)
+	// account := GetSmartAccount(client, address, big.NewInt(0))
+	// fmt.Println("Smart account:", account.Address.Hex())
+	// SendSmartAccountTransaction(client, "https://your-bundler-url", privateKey, big.NewInt(0), common.HexToAddress("0x..."), 0.001)
+
+	// 🔟 Propose, sign and execute a Safe multisig transaction (uncomment to test)
+	// safeTx := BuildSafeTx(client, common.HexToAddress("0xYourSafe"), common.HexToAddress("0x..."), big.NewInt(1e15), nil)
+	// owner2Key, _ := LoadAccount("OWNER_2_PRIVATE_KEY")
+	// sigs := []SafeSignature{SignSafeTx(client, safeTx, privateKey), SignSafeTx(client, safeTx, owner2Key)}
+	// ExecSafeTx(client, privateKey, safeTx, sigs)
+
+	// 1️⃣1️⃣ Watch the mempool for incoming transfers (uncomment to test, use a wss:// URL for subscriptions)
+	// WatchMempool(context.Background(), "wss://your-websocket-rpc", []common.Address{address})
+
+	// 1️⃣2️⃣ Extend the network registry from a chainlist chains.json (uncomment to test)
+	// if count, err := LoadChainlist("chains.json"); err == nil {
+	// 	fmt.Printf("🌐 Loaded %d networks\n", count)
+	// }
+	// network, _ := LookupNetwork(42161)
+	// fmt.Printf("%s: %s, EIP-1559 %t, explorer %s\n", network.Name, network.Currency, network.EIP1559, network.Explorer)
 }
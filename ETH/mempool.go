@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// mempoolPollInterval is how often pending transfers are re-checked and,
+// on HTTP endpoints without subscriptions, how often the pending block is
+// fetched.
+const mempoolPollInterval = 3 * time.Second
+
+// droppedAfterMisses is how many polls in a row a tx must be unknown to
+// the node before it is reported dropped. Load-balanced RPCs often answer
+// from a backend that has not seen the tx yet.
+const droppedAfterMisses = 3
+
+// Final states of a tracked pending transfer.
+const (
+	TransferIncluded = "included"
+	TransferFailed   = "failed"
+	TransferReplaced = "replaced"
+	TransferDropped  = "dropped"
+)
+
+// trackedTx holds the matching transfers of one pending tx and how many
+// polls in a row it has been missing from the node.
+type trackedTx struct {
+	transfers []PendingTransfer
+	misses    int
+}
+
+// PendingTransfer is an unconfirmed native or ERC-20 transfer to a watched
+// address. Token is the zero address for native transfers. Sender and
+// Nonce identify the tx slot, since From differs from Sender for
+// transferFrom.
+type PendingTransfer struct {
+	Hash   common.Hash
+	From   common.Address
+	To     common.Address
+	Token  common.Address
+	Amount *big.Int
+	Sender common.Address
+	Nonce  uint64
+}
+
+// -------------------------------
+// 👀 Watch Mempool
+// -------------------------------
+
+// WatchMempool reports pending transfers to the watched addresses and
+// follows each of them until it is included, replaced or dropped. It uses
+// newPendingTransactions subscriptions on websocket endpoints, with full
+// transactions where the node supports it, and falls back to polling the
+// pending block otherwise. It runs until ctx is done.
+func WatchMempool(ctx context.Context, rpcURL string, watched []common.Address) {
+	rpcClient, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to Ethereum network: %v", err)
+	}
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		log.Fatalf("❌ Failed to get chain ID: %v", err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+
+	targets := make(map[common.Address]bool)
+	for _, address := range watched {
+		targets[address] = true
+	}
+
+	txs := make(chan *types.Transaction)
+	go streamPendingTransactions(ctx, rpcClient, client, txs)
+
+	// A tx can carry both a native and an ERC-20 transfer to a target, so
+	// every matching transfer is kept under its tx hash
+	tracked := make(map[common.Hash]*trackedTx)
+	ticker := time.NewTicker(mempoolPollInterval)
+	defer ticker.Stop()
+
+	fmt.Printf("👀 Watching mempool for %d address(es)\n", len(watched))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tx := <-txs:
+			if _, ok := tracked[tx.Hash()]; ok {
+				continue
+			}
+			transfers := matchPendingTransfers(tx, signer, targets)
+			if len(transfers) > 0 {
+				tracked[tx.Hash()] = &trackedTx{transfers: transfers}
+			}
+			for _, transfer := range transfers {
+				fmt.Printf("⏳ Pending %s from %s to %s (tx %s)\n",
+					formatTransferAmount(client, transfer), transfer.From.Hex(), transfer.To.Hex(), transfer.Hash.Hex())
+			}
+		case <-ticker.C:
+			trackPendingTransfers(ctx, client, tracked)
+		}
+	}
+}
+
+// streamPendingTransactions feeds pending transactions into txs until ctx
+// is done. It subscribes to full transactions, then to hashes on nodes
+// that only send those, and polls on endpoints without subscriptions.
+func streamPendingTransactions(ctx context.Context, rpcClient *rpc.Client, client *ethclient.Client, txs chan<- *types.Transaction) {
+	err := streamFullPendingTransactions(ctx, rpcClient, txs)
+	if err != nil && !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		log.Printf("⚠️ Full pending tx subscription unavailable, subscribing to hashes: %v", err)
+		err = streamPendingHashes(ctx, rpcClient, client, txs)
+	}
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		log.Printf("❌ Pending subscription failed, polling instead: %v", err)
+	}
+	pollPendingBlock(ctx, client, txs)
+}
+
+// streamFullPendingTransactions subscribes with the fullTx flag, which
+// saves an eth_getTransactionByHash per tx. Nodes that ignore the flag
+// send hashes, which fail to decode and end the subscription with an
+// error. It returns nil once ctx is done.
+func streamFullPendingTransactions(ctx context.Context, rpcClient *rpc.Client, txs chan<- *types.Transaction) error {
+	pending := make(chan *types.Transaction)
+	sub, err := rpcClient.EthSubscribe(ctx, pending, "newPendingTransactions", true)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return fmt.Errorf("subscription closed: %w", err)
+		case tx := <-pending:
+			select {
+			case txs <- tx:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// streamPendingHashes subscribes to pending tx hashes and fetches each tx.
+// It returns nil once ctx is done.
+func streamPendingHashes(ctx context.Context, rpcClient *rpc.Client, client *ethclient.Client, txs chan<- *types.Transaction) error {
+	hashes := make(chan common.Hash)
+	sub, err := rpcClient.EthSubscribe(ctx, hashes, "newPendingTransactions")
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return fmt.Errorf("subscription closed: %w", err)
+		case hash := <-hashes:
+			tx, isPending, err := client.TransactionByHash(ctx, hash)
+			if err != nil || !isPending {
+				continue // already gone or mined
+			}
+			select {
+			case txs <- tx:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// pollPendingBlock sends the transactions of the node's pending block that
+// were not seen in the previous poll.
+func pollPendingBlock(ctx context.Context, client *ethclient.Client, txs chan<- *types.Transaction) {
+	ticker := time.NewTicker(mempoolPollInterval)
+	defer ticker.Stop()
+
+	seen := make(map[common.Hash]bool)
+	for {
+		block, err := client.BlockByNumber(ctx, big.NewInt(int64(rpc.PendingBlockNumber)))
+		if err == nil {
+			current := make(map[common.Hash]bool)
+			for _, tx := range block.Transactions() {
+				current[tx.Hash()] = true
+				if seen[tx.Hash()] {
+					continue
+				}
+				select {
+				case txs <- tx:
+				case <-ctx.Done():
+					return
+				}
+			}
+			seen = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// matchPendingTransfers returns the transfers in tx that pay a target,
+// either as native value or as an ERC-20 transfer/transferFrom.
+func matchPendingTransfers(tx *types.Transaction, signer types.Signer, targets map[common.Address]bool) []PendingTransfer {
+	if tx.To() == nil {
+		return nil
+	}
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil
+	}
+
+	var transfers []PendingTransfer
+	if targets[*tx.To()] && tx.Value().Sign() > 0 {
+		transfers = append(transfers, PendingTransfer{
+			Hash: tx.Hash(), From: sender, To: *tx.To(), Amount: tx.Value(), Sender: sender, Nonce: tx.Nonce(),
+		})
+	}
+
+	data := tx.Data()
+	if len(data) < 4 {
+		return transfers
+	}
+	method, err := erc20ABI.MethodById(data[:4])
+	if err != nil || (method.Name != "transfer" && method.Name != "transferFrom") {
+		return transfers
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return transfers
+	}
+	from, to, amount := sender, args[0].(common.Address), args[1].(*big.Int)
+	if method.Name == "transferFrom" {
+		from, to, amount = args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+	}
+	if targets[to] {
+		transfers = append(transfers, PendingTransfer{
+			Hash: tx.Hash(), From: from, To: to, Token: *tx.To(), Amount: amount, Sender: sender, Nonce: tx.Nonce(),
+		})
+	}
+	return transfers
+}
+
+// trackPendingTransfers reports and forgets transfers that were included,
+// or that left the mempool because their nonce was reused or they were
+// evicted. A tx is only taken as evicted after droppedAfterMisses polls.
+func trackPendingTransfers(ctx context.Context, client *ethclient.Client, tracked map[common.Hash]*trackedTx) {
+	for hash, entry := range tracked {
+		// Transfers of one tx share its fate, so the first one is checked
+		status, done := pendingTransferStatus(ctx, client, entry.transfers[0])
+		if !done {
+			entry.misses = 0
+			continue
+		}
+		if status == TransferDropped {
+			if entry.misses++; entry.misses < droppedAfterMisses {
+				continue
+			}
+		}
+		delete(tracked, hash)
+		for _, transfer := range entry.transfers {
+			reportTransferStatus(client, transfer, status)
+		}
+	}
+}
+
+// reportTransferStatus prints the final state of a tracked transfer.
+func reportTransferStatus(client *ethclient.Client, transfer PendingTransfer, status string) {
+	hash := transfer.Hash
+	amount := formatTransferAmount(client, transfer)
+	switch status {
+	case TransferIncluded:
+		fmt.Printf("✅ Confirmed %s to %s (tx %s)\n", amount, transfer.To.Hex(), hash.Hex())
+	case TransferFailed:
+		fmt.Printf("❌ Reverted %s to %s (tx %s)\n", amount, transfer.To.Hex(), hash.Hex())
+	case TransferReplaced:
+		fmt.Printf("🔁 Replaced %s to %s: nonce %d of %s was used by another tx\n", amount, transfer.To.Hex(), transfer.Nonce, transfer.Sender.Hex())
+	case TransferDropped:
+		fmt.Printf("🗑️ Dropped %s to %s (tx %s)\n", amount, transfer.To.Hex(), hash.Hex())
+	}
+}
+
+// pendingTransferStatus returns the final state of a transfer, or false
+// while it is still pending. TransferDropped means the node does not know
+// the tx right now; the caller decides when that is final.
+func pendingTransferStatus(ctx context.Context, client *ethclient.Client, transfer PendingTransfer) (string, bool) {
+	receipt, err := client.TransactionReceipt(ctx, transfer.Hash)
+	if err == nil {
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			return TransferIncluded, true
+		}
+		return TransferFailed, true
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return "", false
+	}
+
+	if _, _, err := client.TransactionByHash(ctx, transfer.Hash); !errors.Is(err, ethereum.NotFound) {
+		return "", false // still pending, or the node is unreachable
+	}
+	// The sender's nonce moved past it, so another tx took its slot
+	nonce, err := client.NonceAt(ctx, transfer.Sender, nil)
+	if err != nil {
+		return "", false
+	}
+	if nonce > transfer.Nonce {
+		return TransferReplaced, true
+	}
+	return TransferDropped, true
+}
+
+// formatTransferAmount renders the amount in ETH or in token units.
+func formatTransferAmount(client *ethclient.Client, transfer PendingTransfer) string {
+	if transfer.Token == (common.Address{}) {
+		return fmt.Sprintf("%f ETH", WeiToEther(transfer.Amount))
+	}
+	symbol, decimals := tokenMetadata(client, transfer.Token)
+	return formatTokenAmount(transfer.Amount, decimals) + " " + symbol
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestNativeAndTokenTransferInOneTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := common.Address{0x42}
+	token := common.Address{0x70}
+	data, err := erc20ABI.Pack("transfer", target, big.NewInt(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(1)
+	signer := types.LatestSignerForChainID(chainID)
+	tx, err := types.SignTx(types.NewTransaction(4, token, big.NewInt(1e15), 60000, big.NewInt(1e9), data), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Watching the token contract too, the tx is a native transfer to it
+	// and a token transfer to the target under the same hash.
+	transfers := matchPendingTransfers(tx, signer, map[common.Address]bool{target: true, token: true})
+	if len(transfers) != 2 {
+		t.Fatalf("matched %d transfers, want the native and the token one", len(transfers))
+	}
+
+	stub, url := newRPCStub(t)
+	stub.handle("eth_getTransactionReceipt", returns(&types.Receipt{
+		Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash(), Logs: []*types.Log{},
+		BlockNumber: big.NewInt(10), GasUsed: 50000, EffectiveGasPrice: big.NewInt(1e9),
+	}))
+	stub.handle("eth_call", func(params []json.RawMessage) (interface{}, error) {
+		var arg stubCallArg
+		if err := json.Unmarshal(params[0], &arg); err != nil {
+			return nil, err
+		}
+		method, err := erc20ABI.MethodById(arg.input()[:4])
+		if err != nil {
+			return nil, err
+		}
+		if method.Name == "symbol" {
+			return packOutputs(method.Outputs.Pack("TKN"))
+		}
+		return packOutputs(method.Outputs.Pack(uint8(2)))
+	})
+	client := dialStub(t, url)
+
+	tracked := map[common.Hash]*trackedTx{tx.Hash(): {transfers: transfers}}
+	trackPendingTransfers(context.Background(), client, tracked)
+	if len(tracked) != 0 {
+		t.Errorf("%d txs still tracked after inclusion", len(tracked))
+	}
+	if n := stub.callCount("eth_getTransactionReceipt"); n != 1 {
+		t.Errorf("fetched the receipt %d times, want once per tx", n)
+	}
+}
+
+func TestDroppedAfterRepeatedMisses(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := common.Address{0x42}
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	tx, err := types.SignTx(types.NewTransaction(4, target, big.NewInt(1e15), 21000, big.NewInt(1e9), nil), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The node answers from a backend that does not know the tx; the
+	// sender's nonce has not moved, so it is neither mined nor replaced.
+	stub, url := newRPCStub(t)
+	stub.handle("eth_getTransactionReceipt", returns(nil))
+	stub.handle("eth_getTransactionByHash", returns(nil))
+	stub.handle("eth_getTransactionCount", returns(hexutil.Uint64(4)))
+	client := dialStub(t, url)
+	tracked := map[common.Hash]*trackedTx{tx.Hash(): {transfers: matchPendingTransfers(tx, signer, map[common.Address]bool{target: true})}}
+
+	poll := func(round string, wantTracked bool) {
+		trackPendingTransfers(context.Background(), client, tracked)
+		if _, ok := tracked[tx.Hash()]; ok != wantTracked {
+			t.Errorf("%s: tracked = %t, want %t", round, ok, wantTracked)
+		}
+	}
+	poll("first miss", true)
+	poll("second miss", true)
+
+	// Seen again, the misses start over.
+	stub.handle("eth_getTransactionByHash", returns(tx))
+	poll("seen", true)
+	stub.handle("eth_getTransactionByHash", returns(nil))
+	for i := 1; i < droppedAfterMisses; i++ {
+		poll("miss after reappearing", true)
+	}
+	poll("final miss", false)
+}
+
+// stubCallArg is the call object of eth_call.
+type stubCallArg struct {
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Input hexutil.Bytes  `json:"input"`
+	Data  hexutil.Bytes  `json:"data"`
+}
+
+func (arg stubCallArg) input() []byte {
+	if len(arg.Input) > 0 {
+		return arg.Input
+	}
+	return arg.Data
+}
+
+func packOutputs(out []byte, err error) (interface{}, error) {
+	return hexutil.Bytes(out), err
+}
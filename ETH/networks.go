@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Network describes an EVM chain.
+type Network struct {
+	ChainID  int64
+	Name     string
+	Currency string // native currency symbol
+	Decimals uint8
+	EIP1559  bool
+	Explorer string // base URL of the block explorer, without trailing slash
+}
+
+// networks is the registry of known EVM chains, keyed by chain ID. It is
+// read while clients connect from several goroutines, hence networksMu.
+var networksMu sync.RWMutex
+
+var networks = map[int64]Network{
+	1:        {1, "Ethereum Mainnet", "ETH", 18, true, "https://etherscan.io"},
+	10:       {10, "Optimism", "ETH", 18, true, "https://optimistic.etherscan.io"},
+	56:       {56, "BNB Smart Chain", "BNB", 18, false, "https://bscscan.com"},
+	61:       {61, "Ethereum Classic", "ETC", 18, false, "https://etc.blockscout.com"},
+	137:      {137, "Polygon Mainnet", "POL", 18, true, "https://polygonscan.com"},
+	204:      {204, "opBNB", "BNB", 18, true, "https://opbnb.bscscan.com"},
+	324:      {324, "zkSync Era", "ETH", 18, true, "https://explorer.zksync.io"},
+	1101:     {1101, "Polygon zkEVM", "ETH", 18, true, "https://zkevm.polygonscan.com"},
+	8453:     {8453, "Base", "ETH", 18, true, "https://basescan.org"},
+	10001:    {10001, "ETHW", "ETHW", 18, true, "https://www.oklink.com/ethw"},
+	42161:    {42161, "Arbitrum One", "ETH", 18, true, "https://arbiscan.io"},
+	59144:    {59144, "Linea", "ETH", 18, true, "https://lineascan.build"},
+	534352:   {534352, "Scroll", "ETH", 18, true, "https://scrollscan.com"},
+	97:       {97, "BNB Smart Chain Testnet", "tBNB", 18, false, "https://testnet.bscscan.com"},
+	5611:     {5611, "opBNB Testnet", "tBNB", 18, true, "https://opbnb-testnet.bscscan.com"},
+	80002:    {80002, "Polygon Amoy Testnet", "POL", 18, true, "https://amoy.polygonscan.com"},
+	84532:    {84532, "Base Sepolia Testnet", "ETH", 18, true, "https://sepolia.basescan.org"},
+	534351:   {534351, "Scroll Sepolia Testnet", "ETH", 18, true, "https://sepolia.scrollscan.com"},
+	11155111: {11155111, "Eth Sepolia Testnet", "ETH", 18, true, "https://sepolia.etherscan.io"},
+	11155420: {11155420, "Optimism Sepolia Testnet", "ETH", 18, true, "https://sepolia-optimism.etherscan.io"},
+}
+
+// verifiedChains records the chain ID each client was checked against when
+// it was connected, so signing can refuse unchecked clients and endpoints
+// that change chains. Clients are connected from several goroutines, hence
+// the mutex.
+var (
+	verifiedChainsMu sync.Mutex
+	verifiedChains   = make(map[*ethclient.Client]int64)
+)
+
+// LookupNetwork returns the registered network for a chain ID.
+func LookupNetwork(chainID int64) (Network, bool) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+	network, ok := networks[chainID]
+	return network, ok
+}
+
+// LookupNetworkByName returns the registered network with the given name,
+// ignoring case.
+func LookupNetworkByName(name string) (Network, bool) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+	for _, network := range networks {
+		if strings.EqualFold(network.Name, name) {
+			return network, true
+		}
+	}
+	return Network{}, false
+}
+
+// RegisterNetwork adds or replaces a network in the registry.
+func RegisterNetwork(network Network) {
+	networksMu.Lock()
+	defer networksMu.Unlock()
+	networks[network.ChainID] = network
+}
+
+// ExplorerTxURL returns the explorer link of a transaction, or "" if the
+// chain has no known explorer.
+func ExplorerTxURL(chainID int64, txHash string) string {
+	network, ok := LookupNetwork(chainID)
+	if !ok || network.Explorer == "" {
+		return ""
+	}
+	return network.Explorer + "/tx/" + txHash
+}
+
+// -------------------------------
+// 🔗 Connect with Chain ID Check
+// -------------------------------
+
+// ConnectNetwork dials rpcURL and verifies that eth_chainId matches the
+// expected chain before returning the client. Only clients connected this
+// way are allowed to sign.
+func ConnectNetwork(rpcURL string, chainID int64) *ethclient.Client {
+	client, err := tryConnectNetwork(rpcURL, chainID)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	return client
+}
+
+// ConnectNamedNetwork connects to a registered network by name, checking
+// the RPC against the chain ID the registry has for that name.
+func ConnectNamedNetwork(name, rpcURL string) *ethclient.Client {
+	client, err := tryConnectNamedNetwork(name, rpcURL)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	return client
+}
+
+// tryConnectNetwork is ConnectNetwork returning the error, for callers
+// that go on with other networks when one is down or misconfigured.
+func tryConnectNetwork(rpcURL string, chainID int64) (*ethclient.Client, error) {
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", rpcURL, err)
+	}
+	if err := verifyClientChain(client, chainID); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("RPC %s: %v", rpcURL, err)
+	}
+	return client, nil
+}
+
+// tryConnectNamedNetwork is ConnectNamedNetwork returning the error.
+func tryConnectNamedNetwork(name, rpcURL string) (*ethclient.Client, error) {
+	network, ok := LookupNetworkByName(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q, register it or load a chainlist first", name)
+	}
+	return tryConnectNetwork(rpcURL, network.ChainID)
+}
+
+// verifyClientChain checks that a freshly dialed client serves the
+// expected chain and records it as verified.
+func verifyClientChain(client *ethclient.Client, expected int64) error {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	if chainID.Int64() != expected {
+		return fmt.Errorf("serves chain %s, expected %s", describeChain(chainID.Int64()), describeChain(expected))
+	}
+	verifiedChainsMu.Lock()
+	verifiedChains[client] = expected
+	verifiedChainsMu.Unlock()
+	return nil
+}
+
+// verifiedChainID returns the chain ID recorded when client was connected.
+func verifiedChainID(client *ethclient.Client) (int64, bool) {
+	verifiedChainsMu.Lock()
+	defer verifiedChainsMu.Unlock()
+	chainID, ok := verifiedChains[client]
+	return chainID, ok
+}
+
+// signingChainError refuses clients that were not connected through
+// ConnectNetwork and clients whose RPC now reports another chain ID.
+func signingChainError(client *ethclient.Client, chainID int64) error {
+	expected, ok := verifiedChainID(client)
+	if !ok {
+		return fmt.Errorf("refusing to sign: client was not connected with ConnectNetwork or ConnectNamedNetwork")
+	}
+	if expected != chainID {
+		return fmt.Errorf("refusing to sign: RPC now serves chain %s, expected %s", describeChain(chainID), describeChain(expected))
+	}
+	return nil
+}
+
+// checkSigningChain exits instead of signing for the wrong chain.
+func checkSigningChain(client *ethclient.Client, chainID int64) {
+	if err := signingChainError(client, chainID); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// describeChain renders a chain ID with its registered name.
+func describeChain(chainID int64) string {
+	if network, ok := LookupNetwork(chainID); ok {
+		return fmt.Sprintf("%d (%s)", chainID, network.Name)
+	}
+	return fmt.Sprintf("%d (unknown)", chainID)
+}
+
+// -------------------------------
+// 📥 Load Chainlist Networks
+// -------------------------------
+
+// chainlistEntry is one chain in chainlist / chainid.network chains.json.
+type chainlistEntry struct {
+	Name           string `json:"name"`
+	ChainID        int64  `json:"chainId"`
+	NativeCurrency struct {
+		Symbol   string `json:"symbol"`
+		Decimals uint8  `json:"decimals"`
+	} `json:"nativeCurrency"`
+	Explorers []struct {
+		URL string `json:"url"`
+	} `json:"explorers"`
+	Features []struct {
+		Name string `json:"name"`
+	} `json:"features"`
+}
+
+// LoadChainlist registers every chain of a chainlist-format JSON file,
+// replacing built-in entries with the same chain ID. It returns the number
+// of networks loaded.
+func LoadChainlist(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var entries []chainlistEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return 0, fmt.Errorf("invalid chainlist JSON: %w", err)
+	}
+
+	loaded := 0
+	for _, entry := range entries {
+		if entry.ChainID == 0 {
+			continue
+		}
+		network := Network{
+			ChainID:  entry.ChainID,
+			Name:     entry.Name,
+			Currency: entry.NativeCurrency.Symbol,
+			Decimals: entry.NativeCurrency.Decimals,
+		}
+		for _, feature := range entry.Features {
+			if feature.Name == "EIP1559" {
+				network.EIP1559 = true
+			}
+		}
+		if len(entry.Explorers) > 0 {
+			network.Explorer = strings.TrimSuffix(entry.Explorers[0].URL, "/")
+		}
+		RegisterNetwork(network)
+		loaded++
+	}
+	return loaded, nil
+}
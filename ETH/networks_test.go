@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestSigningChainCheck(t *testing.T) {
+	stub, url := newRPCStub(t)
+	stub.handle("eth_chainId", returns(hexutil.Uint64(8453)))
+
+	client := ConnectNamedNetwork("base", url)
+	defer client.Close()
+	if err := signingChainError(client, 8453); err != nil {
+		t.Errorf("verified client refused: %v", err)
+	}
+	if err := signingChainError(client, 10); err == nil || !strings.Contains(err.Error(), "10 (Optimism)") {
+		t.Errorf("err = %v, want a refusal naming the new chain", err)
+	}
+
+	unverified := dialStub(t, url)
+	if err := signingChainError(unverified, 8453); err == nil || !strings.Contains(err.Error(), "not connected") {
+		t.Errorf("err = %v, want a refusal for a client that was never verified", err)
+	}
+}
+
+func TestConnectNetworkConcurrently(t *testing.T) {
+	stub, url := newRPCStub(t)
+	stub.handle("eth_chainId", returns(hexutil.Uint64(1)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client := ConnectNetwork(url, 1)
+			defer client.Close()
+			if chainID, ok := verifiedChainID(client); !ok || chainID != 1 {
+				t.Errorf("recorded chain %d, %t, want 1", chainID, ok)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			RegisterNetwork(Network{ChainID: int64(900000 + i), Name: "Concurrent Testnet"})
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConnectClientCannotSign(t *testing.T) {
+	stub, url := newRPCStub(t)
+	stub.handle("eth_chainId", returns(hexutil.Uint64(1)))
+
+	client := ConnectClient(url)
+	defer client.Close()
+	if err := signingChainError(client, 1); err == nil || !strings.Contains(err.Error(), "not connected with ConnectNetwork") {
+		t.Errorf("err = %v, want a refusal for a client from ConnectClient", err)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWaitForReceiptTimeout(t *testing.T) {
+	defer func(interval time.Duration) { receiptPollInterval = interval }(receiptPollInterval)
+	receiptPollInterval = time.Millisecond
+
+	// A tx that is never mined, e.g. dropped for being underpriced.
+	stub, url := newRPCStub(t)
+	stub.handle("eth_getTransactionReceipt", returns(nil))
+	client := dialStub(t, url)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := WaitForReceipt(ctx, client, common.HexToHash("0x01")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context deadline", err)
+	}
+	if stub.callCount("eth_getTransactionReceipt") < 2 {
+		t.Error("gave up without polling again")
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Safe operations.
+const (
+	SafeCall         = 0
+	SafeDelegateCall = 1
+)
+
+var safeABI = mustParseABI(`[
+	{"name":"nonce","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"getThreshold","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"getOwners","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]},
+	{"name":"approvedHashes","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"hash","type":"bytes32"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"execTransaction","type":"function","inputs":[
+		{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},
+		{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},
+		{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},
+		{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]}
+]`)
+
+// SafeTx is a Safe transaction awaiting owner signatures. Gas refund
+// fields stay zero so the executor pays gas directly.
+type SafeTx struct {
+	Safe           common.Address
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// Signature types Safe tells apart by the v byte of a 65-byte signature.
+const (
+	safeSigContract     = 0  // EIP-1271, r is the verifier and s an offset
+	safeSigApprovedHash = 1  // r is an owner who called approveHash or executes
+	safeSigEthSignBase  = 31 // eth_sign over the safeTxHash, v + 4
+)
+
+// SafeSignature is one owner's signature over a safeTxHash.
+type SafeSignature struct {
+	Signer    common.Address
+	Signature []byte
+}
+
+// -------------------------------
+// 📝 Propose Safe Transaction
+// -------------------------------
+
+// BuildSafeTx prepares a call from the Safe using its current nonce.
+func BuildSafeTx(client *ethclient.Client, safe, to common.Address, value *big.Int, data []byte) SafeTx {
+	values, err := callView(client, safe, safeABI, "nonce")
+	if err != nil {
+		log.Fatalf("❌ Failed to get Safe nonce: %v", err)
+	}
+	return SafeTx{
+		Safe:      safe,
+		To:        to,
+		Value:     value,
+		Data:      data,
+		Operation: SafeCall,
+		SafeTxGas: new(big.Int),
+		BaseGas:   new(big.Int),
+		GasPrice:  new(big.Int),
+		Nonce:     values[0].(*big.Int),
+	}
+}
+
+// typedData returns the EIP-712 form of the transaction (Safe >= 1.3.0,
+// whose domain is chainId + verifyingContract).
+func (tx SafeTx) typedData(chainID *big.Int) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain: apitypes.TypedDataDomain{
+			ChainId:           (*math.HexOrDecimal256)(chainID),
+			VerifyingContract: tx.Safe.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":             tx.To.Hex(),
+			"value":          tx.Value,
+			"data":           tx.Data,
+			"operation":      big.NewInt(int64(tx.Operation)),
+			"safeTxGas":      tx.SafeTxGas,
+			"baseGas":        tx.BaseGas,
+			"gasPrice":       tx.GasPrice,
+			"gasToken":       tx.GasToken.Hex(),
+			"refundReceiver": tx.RefundReceiver.Hex(),
+			"nonce":          tx.Nonce,
+		},
+	}
+}
+
+// SafeTxHash returns the EIP-712 safeTxHash owners sign.
+func SafeTxHash(tx SafeTx, chainID *big.Int) common.Hash {
+	typedData := tx.typedData(chainID)
+	typedData.Types["EIP712Domain"] = eip712DomainType(typedData.Domain)
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		log.Fatalf("❌ Failed to hash Safe transaction: %v", err)
+	}
+	return common.BytesToHash(hash)
+}
+
+// -------------------------------
+// ✍️ Sign Safe Transaction
+// -------------------------------
+
+// SignSafeTx signs the safeTxHash for the client's chain with an owner key
+// loaded via LoadAccount.
+func SignSafeTx(client *ethclient.Client, tx SafeTx, privateKey *ecdsa.PrivateKey) SafeSignature {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Failed to get chain ID: %v", err)
+	}
+	checkSigningChain(client, chainID.Int64())
+
+	signer := crypto.PubkeyToAddress(privateKey.PublicKey)
+	sig := signTypedData(privateKey, tx.typedData(chainID))
+	fmt.Printf("✍️ %s signed safeTxHash %s\n", signer.Hex(), SafeTxHash(tx, chainID).Hex())
+	return SafeSignature{Signer: signer, Signature: sig}
+}
+
+// SafeApprovalSignature is the signature of an owner who approved the
+// safeTxHash on chain with approveHash, or who executes the transaction.
+func SafeApprovalSignature(owner common.Address) SafeSignature {
+	sig := make([]byte, 65)
+	copy(sig[12:32], owner.Bytes())
+	sig[64] = safeSigApprovedHash
+	return SafeSignature{Signer: owner, Signature: sig}
+}
+
+// CombineSafeSignatures concatenates signatures sorted by signer address
+// in ascending order, as Safe's checkSignatures requires.
+func CombineSafeSignatures(sigs []SafeSignature) []byte {
+	sorted := append([]SafeSignature(nil), sigs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Signer.Bytes(), sorted[j].Signer.Bytes()) < 0
+	})
+
+	var combined []byte
+	for i, sig := range sorted {
+		if i > 0 && sig.Signer == sorted[i-1].Signer {
+			continue
+		}
+		combined = append(combined, sig.Signature...)
+	}
+	return combined
+}
+
+// -------------------------------
+// 🚀 Execute Safe Transaction
+// -------------------------------
+
+// ExecSafeTx verifies that the collected signatures come from distinct
+// owners and meet the threshold, then sends execTransaction from the
+// executor key, which does not need to be an owner.
+func ExecSafeTx(client *ethclient.Client, executorKey *ecdsa.PrivateKey, tx SafeTx, sigs []SafeSignature) {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Failed to get chain ID: %v", err)
+	}
+	checkSigningChain(client, chainID.Int64())
+	hash := SafeTxHash(tx, chainID)
+	executor := crypto.PubkeyToAddress(executorKey.PublicKey)
+
+	values, err := callView(client, tx.Safe, safeABI, "getOwners")
+	if err != nil {
+		log.Fatalf("❌ Failed to get Safe owners: %v", err)
+	}
+	owners := make(map[common.Address]bool)
+	for _, owner := range values[0].([]common.Address) {
+		owners[owner] = true
+	}
+	values, err = callView(client, tx.Safe, safeABI, "getThreshold")
+	if err != nil {
+		log.Fatalf("❌ Failed to get Safe threshold: %v", err)
+	}
+	threshold := values[0].(*big.Int)
+
+	signers := make(map[common.Address]bool)
+	for _, sig := range sigs {
+		if err := verifySafeSignature(client, tx.Safe, hash, executor, sig); err != nil {
+			log.Fatalf("❌ Signature of %s: %v", sig.Signer.Hex(), err)
+		}
+		if !owners[sig.Signer] {
+			log.Fatalf("❌ %s is not an owner of Safe %s", sig.Signer.Hex(), tx.Safe.Hex())
+		}
+		signers[sig.Signer] = true
+	}
+	if int64(len(signers)) < threshold.Int64() {
+		log.Fatalf("❌ %d of %s required owner signatures collected", len(signers), threshold)
+	}
+
+	data, err := safeABI.Pack("execTransaction", tx.To, tx.Value, tx.Data, tx.Operation,
+		tx.SafeTxGas, tx.BaseGas, tx.GasPrice, tx.GasToken, tx.RefundReceiver, CombineSafeSignatures(sigs))
+	if err != nil {
+		log.Fatalf("❌ Failed to encode execTransaction: %v", err)
+	}
+	fmt.Printf("🔐 Executing Safe transaction %s with %d/%s signatures\n", hash.Hex(), len(signers), threshold)
+	SendTransactionWithOptions(client, executorKey, tx.Safe, 0, SendOptions{Data: data, Simulate: true, ABI: &safeABI})
+}
+
+// verifySafeSignature checks one signature the way Safe's checkSignatures
+// will, so a bad signature fails before gas is spent. Contract signatures
+// need their dynamic part appended after all static parts, which
+// CombineSafeSignatures does not build, so they are rejected.
+func verifySafeSignature(client *ethclient.Client, safe common.Address, hash common.Hash, executor common.Address, sig SafeSignature) error {
+	if len(sig.Signature) != 65 {
+		return fmt.Errorf("is %d bytes, expected 65", len(sig.Signature))
+	}
+
+	v := sig.Signature[64]
+	digest := hash.Bytes()
+	switch {
+	case v == safeSigContract:
+		return fmt.Errorf("contract signatures are not supported")
+	case v == safeSigApprovedHash:
+		if common.BytesToAddress(sig.Signature[:32]) != sig.Signer {
+			return fmt.Errorf("approval is for %s", common.BytesToAddress(sig.Signature[:32]).Hex())
+		}
+		if sig.Signer == executor {
+			return nil
+		}
+		values, err := callView(client, safe, safeABI, "approvedHashes", sig.Signer, hash)
+		if err != nil {
+			return fmt.Errorf("failed to check approved hash: %v", err)
+		}
+		if values[0].(*big.Int).Sign() == 0 {
+			return fmt.Errorf("safeTxHash %s is not approved on chain", hash.Hex())
+		}
+		return nil
+	case v == 27 || v == 28:
+		v -= 27
+	case v == safeSigEthSignBase || v == safeSigEthSignBase+1:
+		v -= safeSigEthSignBase
+		digest = accounts.TextHash(digest)
+	default:
+		return fmt.Errorf("unsupported v %d", v)
+	}
+
+	recoverable := append(append([]byte(nil), sig.Signature[:64]...), v)
+	pubKey, err := crypto.SigToPub(digest, recoverable)
+	if err != nil || crypto.PubkeyToAddress(*pubKey) != sig.Signer {
+		return fmt.Errorf("does not match safeTxHash %s", hash.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// simulatedChainID is the chain ID of the go-ethereum simulated backend.
+const simulatedChainID = 1337
+
+var safeSetupABI = mustParseABI(`[
+	{"name":"setup","type":"function","inputs":[
+		{"name":"_owners","type":"address[]"},{"name":"_threshold","type":"uint256"},{"name":"to","type":"address"},
+		{"name":"data","type":"bytes"},{"name":"fallbackHandler","type":"address"},{"name":"paymentToken","type":"address"},
+		{"name":"payment","type":"uint256"},{"name":"paymentReceiver","type":"address"}],"outputs":[]},
+	{"name":"approveHash","type":"function","inputs":[{"name":"hashToApprove","type":"bytes32"}],"outputs":[]}
+]`)
+
+var safeProxyFactoryABI = mustParseABI(`[
+	{"name":"createProxyWithNonce","type":"function","inputs":[
+		{"name":"_singleton","type":"address"},{"name":"initializer","type":"bytes"},{"name":"saltNonce","type":"uint256"}],
+		"outputs":[{"name":"proxy","type":"address"}]},
+	{"name":"ProxyCreation","type":"event","anonymous":false,"inputs":[
+		{"name":"proxy","type":"address","indexed":true},{"name":"singleton","type":"address","indexed":false}]}
+]`)
+
+// safeChain is a simulated chain with a Safe proxy deployed through the
+// real singleton and proxy factory.
+type safeChain struct {
+	t       *testing.T
+	backend *simulated.Backend
+	client  *ethclient.Client
+	funder  *ecdsa.PrivateKey
+	safe    common.Address
+}
+
+func newSafeChain(t *testing.T, owners []common.Address, threshold int64) *safeChain {
+	funders, _ := generateKeys(t, 1)
+	alloc := types.GenesisAlloc{crypto.PubkeyToAddress(funders[0].PublicKey): {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))}}
+	for _, owner := range owners {
+		alloc[owner] = types.Account{Balance: big.NewInt(1e18)}
+	}
+	ipcPath := filepath.Join(t.TempDir(), "sim.ipc")
+	backend := simulated.NewBackend(alloc, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.IPCPath = ipcPath
+	})
+	t.Cleanup(func() { backend.Close() })
+	client := ConnectNetwork(ipcPath, simulatedChainID)
+	t.Cleanup(client.Close)
+	chain := &safeChain{t: t, backend: backend, client: client, funder: funders[0]}
+
+	singleton := chain.deploy("safe_v1.4.1.bin")
+	factory := chain.deploy("safe_proxy_factory_v1.4.1.bin")
+	setup, err := safeSetupABI.Pack("setup", owners, big.NewInt(threshold), common.Address{}, []byte{},
+		common.Address{}, common.Address{}, new(big.Int), common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	create, err := safeProxyFactoryABI.Pack("createProxyWithNonce", singleton, setup, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := chain.send(chain.funder, factory, 0, SendOptions{Data: create})
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("createProxyWithNonce reverted")
+	}
+	for _, log := range receipt.Logs {
+		if log.Topics[0] == safeProxyFactoryABI.Events["ProxyCreation"].ID {
+			chain.safe = common.BytesToAddress(log.Topics[1].Bytes())
+		}
+	}
+	if chain.safe == (common.Address{}) {
+		t.Fatal("no ProxyCreation event")
+	}
+	chain.send(chain.funder, chain.safe, 1, SendOptions{})
+	return chain
+}
+
+// deploy creates the contract whose creation bytecode is in testdata; the
+// Safe singleton and proxy factory are the safe-contracts v1.4.1 builds.
+func (c *safeChain) deploy(file string) common.Address {
+	code, err := os.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	bin, err := hex.DecodeString(strings.TrimSpace(string(code)))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	ctx := context.Background()
+	nonce, err := c.client.PendingNonceAt(ctx, crypto.PubkeyToAddress(c.funder.PublicKey))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	gasPrice, err := c.client.SuggestGasPrice(ctx)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	tx, err := types.SignTx(types.NewContractCreation(nonce, new(big.Int), 8000000, gasPrice, bin),
+		types.LatestSignerForChainID(big.NewInt(simulatedChainID)), c.funder)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.client.SendTransaction(ctx, tx); err != nil {
+		c.t.Fatal(err)
+	}
+	receipt := c.mine(tx.Hash())
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		c.t.Fatalf("deploying %s failed", file)
+	}
+	return receipt.ContractAddress
+}
+
+// send sends a transaction through trySendTransaction and mines it.
+func (c *safeChain) send(key *ecdsa.PrivateKey, to common.Address, amountEther float64, opts SendOptions) *types.Receipt {
+	tx, err := trySendTransaction(c.client, key, to, amountEther, opts)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return c.mine(tx.Hash())
+}
+
+func (c *safeChain) mine(txHash common.Hash) *types.Receipt {
+	c.backend.Commit()
+	receipt, err := c.client.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		c.t.Fatalf("receipt of %s: %v", txHash.Hex(), err)
+	}
+	return receipt
+}
+
+func (c *safeChain) balance(account common.Address) *big.Int {
+	balance, err := c.client.BalanceAt(context.Background(), account, nil)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return balance
+}
+
+func (c *safeChain) nonce() int64 {
+	values, err := callView(c.client, c.safe, safeABI, "nonce")
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return values[0].(*big.Int).Int64()
+}
+
+func generateKeys(t *testing.T, n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	addresses := make([]common.Address, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return keys, addresses
+}
+
+func TestExecSafeTx(t *testing.T) {
+	keys, owners := generateKeys(t, 3)
+	executorKeys, executors := generateKeys(t, 1)
+	chain := newSafeChain(t, owners, 2)
+	chain.send(chain.funder, executors[0], 1, SendOptions{})
+	recipient := common.Address{0xbe, 0xef}
+
+	tx := BuildSafeTx(chain.client, chain.safe, recipient, big.NewInt(1e15), nil)
+	if tx.Nonce.Sign() != 0 {
+		t.Fatalf("nonce = %s, want 0 on a new Safe", tx.Nonce)
+	}
+
+	// Collect signatures in descending owner order; ExecSafeTx must sort them.
+	sigs := []SafeSignature{SignSafeTx(chain.client, tx, keys[0]), SignSafeTx(chain.client, tx, keys[2])}
+	sort.Slice(sigs, func(i, j int) bool { return bytes.Compare(sigs[i].Signer.Bytes(), sigs[j].Signer.Bytes()) > 0 })
+	ExecSafeTx(chain.client, executorKeys[0], tx, sigs)
+	chain.backend.Commit()
+
+	if balance := chain.balance(recipient); balance.Cmp(big.NewInt(1e15)) != 0 {
+		t.Errorf("recipient balance = %s, want 1e15", balance)
+	}
+	if nonce := chain.nonce(); nonce != 1 {
+		t.Errorf("Safe nonce = %d after execution, want 1", nonce)
+	}
+}
+
+func TestExecSafeTxRejectedOnChain(t *testing.T) {
+	keys, owners := generateKeys(t, 3)
+	chain := newSafeChain(t, owners, 2)
+	recipient := common.Address{0xbe, 0xef}
+	tx := BuildSafeTx(chain.client, chain.safe, recipient, big.NewInt(1e15), nil)
+	sigs := []SafeSignature{SignSafeTx(chain.client, tx, keys[0]), SignSafeTx(chain.client, tx, keys[1])}
+	sort.Slice(sigs, func(i, j int) bool { return bytes.Compare(sigs[i].Signer.Bytes(), sigs[j].Signer.Bytes()) > 0 })
+
+	tests := []struct {
+		name       string
+		signatures []byte
+	}{
+		{"descending signers", append(append([]byte(nil), sigs[0].Signature...), sigs[1].Signature...)},
+		{"below threshold", CombineSafeSignatures(sigs[:1])},
+	}
+	for _, test := range tests {
+		data, err := safeABI.Pack("execTransaction", tx.To, tx.Value, tx.Data, tx.Operation,
+			tx.SafeTxGas, tx.BaseGas, tx.GasPrice, tx.GasToken, tx.RefundReceiver, test.signatures)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A fixed gas limit skips estimation so the tx reaches the chain.
+		receipt := chain.send(keys[2], chain.safe, 0, SendOptions{Data: data, GasLimit: 300000})
+		if receipt.Status != types.ReceiptStatusFailed {
+			t.Errorf("%s: execTransaction succeeded", test.name)
+		}
+	}
+
+	if balance := chain.balance(recipient); balance.Sign() != 0 {
+		t.Errorf("recipient balance = %s after rejected executions", balance)
+	}
+	if nonce := chain.nonce(); nonce != 0 {
+		t.Errorf("Safe nonce = %d after rejected executions, want 0", nonce)
+	}
+}
+
+func TestExecSafeTxMixedSignatureTypes(t *testing.T) {
+	keys, owners := generateKeys(t, 3)
+	chain := newSafeChain(t, owners, 3)
+	recipient := common.Address{0xbe, 0xef}
+	tx := BuildSafeTx(chain.client, chain.safe, recipient, big.NewInt(1e15), nil)
+	hash := SafeTxHash(tx, big.NewInt(simulatedChainID))
+
+	// An eth_sign signature, an on-chain approval and the executor's own
+	// approval.
+	ethSign, err := crypto.Sign(accounts.TextHash(hash.Bytes()), keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethSign[64] += safeSigEthSignBase
+	approve, err := safeSetupABI.Pack("approveHash", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt := chain.send(keys[2], chain.safe, 0, SendOptions{Data: approve}); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("approveHash reverted")
+	}
+
+	sigs := []SafeSignature{
+		{Signer: owners[1], Signature: ethSign},
+		SafeApprovalSignature(owners[2]),
+		SafeApprovalSignature(owners[0]),
+	}
+	ExecSafeTx(chain.client, keys[0], tx, sigs)
+	chain.backend.Commit()
+
+	if nonce := chain.nonce(); nonce != 1 {
+		t.Errorf("Safe nonce = %d after execution, want 1", nonce)
+	}
+	if balance := chain.balance(recipient); balance.Cmp(big.NewInt(1e15)) != 0 {
+		t.Errorf("recipient balance = %s, want 1e15", balance)
+	}
+}
+
+func TestVerifySafeSignature(t *testing.T) {
+	keys, owners := generateKeys(t, 2)
+	chain := newSafeChain(t, owners, 1)
+	tx := BuildSafeTx(chain.client, chain.safe, common.Address{1}, big.NewInt(0), nil)
+	hash := SafeTxHash(tx, big.NewInt(simulatedChainID))
+	valid := SignSafeTx(chain.client, tx, keys[0])
+
+	withV := func(v byte) []byte {
+		sig := append([]byte(nil), valid.Signature...)
+		sig[64] = v
+		return sig
+	}
+	tests := []struct {
+		name string
+		sig  SafeSignature
+		err  string
+	}{
+		{"eip712", valid, ""},
+		{"executor approval", SafeApprovalSignature(owners[1]), ""},
+		{"short", SafeSignature{owners[0], valid.Signature[:64]}, "expected 65"},
+		{"empty", SafeSignature{owners[0], nil}, "expected 65"},
+		{"contract", SafeSignature{owners[0], withV(0)}, "contract signatures"},
+		{"unknown v", SafeSignature{owners[0], withV(5)}, "unsupported v 5"},
+		{"eth_sign v on a typed signature", SafeSignature{owners[0], withV(valid.Signature[64] + 4)}, "does not match"},
+		{"wrong signer", SafeSignature{owners[1], valid.Signature}, "does not match"},
+		{"approval not on chain", SafeApprovalSignature(owners[0]), "not approved"},
+		{"approval for someone else", SafeSignature{owners[0], SafeApprovalSignature(owners[1]).Signature}, "approval is for"},
+	}
+	for _, test := range tests {
+		err := verifySafeSignature(chain.client, chain.safe, hash, owners[1], test.sig)
+		if test.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%s: err = %v, want %q", test.name, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// erc20ABI is the subset of ERC-20 used across the ETH tools.
+var erc20ABI = mustParseABI(`[
+	{"name":"name","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"name":"symbol","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"name":"decimals","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"name":"balanceOf","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"allowance","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"transfer","type":"function","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"transferFrom","type":"function","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"approve","type":"function","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"Transfer","type":"event","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"name":"Approval","type":"event","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`)
+
+// BalanceChange is the expected change of one account's balance. Token is
+// the zero address for the native coin.
+type BalanceChange struct {
+	Account common.Address
+	Token   common.Address
+	Delta   *big.Int
+}
+
+// SimulationResult is the outcome of a dry run at pending state.
+type SimulationResult struct {
+	GasUsed        uint64
+	ReturnData     []byte
+	Reverted       bool
+	RevertReason   string
+	BalanceChanges []BalanceChange
+}
+
+// -------------------------------
+// 🧪 Simulate Transaction
+// -------------------------------
+
+// SimulateTransaction executes an unsigned tx from the sender with
+// eth_call at pending state and estimates its gas. Revert reasons and
+// custom errors are decoded, the latter from contractABI when given.
+func SimulateTransaction(client *ethclient.Client, from common.Address, tx *types.Transaction, contractABI *abi.ABI) SimulationResult {
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+
+	var result SimulationResult
+	gasUsed, err := client.EstimateGas(context.Background(), msg)
+	if err != nil {
+		result.Reverted = true
+		result.RevertReason = decodeRevert(err, contractABI)
+		return result
+	}
+	result.GasUsed = gasUsed
+
+	msg.Gas = tx.Gas()
+	result.ReturnData, err = client.PendingCallContract(context.Background(), msg)
+	if err != nil {
+		result.Reverted = true
+		result.RevertReason = decodeRevert(err, contractABI)
+		return result
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), tx.GasPrice())
+	if txFee, err := EstimateTxFee(client, tx); err == nil {
+		fee.Add(fee, txFee.L1Fee)
+	}
+	result.BalanceChanges = expectedBalanceChanges(from, tx, fee)
+	return result
+}
+
+// decodeRevert extracts a readable reason from a failed call: Error(string),
+// Panic(uint256), or a custom error declared in contractABI.
+func decodeRevert(err error, contractABI *abi.ABI) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error()
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error()
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil || len(data) < 4 {
+		return err.Error()
+	}
+
+	if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+		return reason
+	}
+	if contractABI != nil {
+		if customErr, lookupErr := contractABI.ErrorByID([4]byte(data[:4])); lookupErr == nil {
+			args, unpackErr := customErr.Unpack(data)
+			if unpackErr == nil {
+				return fmt.Sprintf("%s%v", customErr.Name, args)
+			}
+		}
+	}
+	return fmt.Sprintf("%s (data %s)", err.Error(), hexData)
+}
+
+// expectedBalanceChanges derives native and ERC-20 balance changes from
+// the tx value, fee and, for token transfers, its call data.
+func expectedBalanceChanges(from common.Address, tx *types.Transaction, fee *big.Int) []BalanceChange {
+	spent := new(big.Int).Add(tx.Value(), fee)
+	changes := []BalanceChange{{Account: from, Delta: new(big.Int).Neg(spent)}}
+	if tx.To() != nil && tx.Value().Sign() > 0 {
+		changes = append(changes, BalanceChange{Account: *tx.To(), Delta: tx.Value()})
+	}
+
+	if tx.To() == nil || len(tx.Data()) < 4 {
+		return changes
+	}
+	method, err := erc20ABI.MethodById(tx.Data()[:4])
+	if err != nil {
+		return changes
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return changes
+	}
+
+	token := *tx.To()
+	switch method.Name {
+	case "transfer":
+		to, amount := args[0].(common.Address), args[1].(*big.Int)
+		changes = append(changes,
+			BalanceChange{Account: from, Token: token, Delta: new(big.Int).Neg(amount)},
+			BalanceChange{Account: to, Token: token, Delta: amount})
+	case "transferFrom":
+		owner, to, amount := args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+		changes = append(changes,
+			BalanceChange{Account: owner, Token: token, Delta: new(big.Int).Neg(amount)},
+			BalanceChange{Account: to, Token: token, Delta: amount})
+	}
+	return changes
+}
+
+// PrintSimulation renders a SimulationResult.
+func PrintSimulation(result SimulationResult) {
+	if result.Reverted {
+		fmt.Println("❌ Simulation reverted:", result.RevertReason)
+		return
+	}
+	fmt.Printf("🧪 Simulation succeeded, gas used: %d\n", result.GasUsed)
+	if len(result.ReturnData) > 0 {
+		fmt.Println("↩️ Return data:", hexutil.Encode(result.ReturnData))
+	}
+	for _, change := range result.BalanceChanges {
+		if change.Token == (common.Address{}) {
+			fmt.Printf("   %s: %+f ETH\n", change.Account.Hex(), WeiToEther(change.Delta))
+		} else {
+			fmt.Printf("   %s: %+d units of token %s\n", change.Account.Hex(), change.Delta, change.Token.Hex())
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// EntryPointV07 is the canonical ERC-4337 v0.7 EntryPoint.
+	EntryPointV07 = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+	// SimpleAccountFactoryV07 is the eth-infinitism SimpleAccountFactory for v0.7.
+	SimpleAccountFactoryV07 = common.HexToAddress("0x91E60e0613810449d098b0b5Ec8b51A0FE8c8985")
+)
+
+// simpleAccountDummySignature passes SimpleAccount's ECDSA recovery during
+// gas estimation without being a valid signature.
+var simpleAccountDummySignature = hexutil.MustDecode("0xfffffffffffffffffffffffffffffff0000000000000000000000000000000007aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1c")
+
+var smartAccountABI = mustParseABI(`[
+	{"name":"getAddress","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"salt","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"name":"createAccount","type":"function","inputs":[{"name":"owner","type":"address"},{"name":"salt","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"name":"getNonce","type":"function","stateMutability":"view","inputs":[{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"execute","type":"function","inputs":[{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}],"outputs":[]}
+]`)
+
+// SmartAccount is a SimpleAccount owned by a single ECDSA key.
+type SmartAccount struct {
+	Address  common.Address
+	Owner    common.Address
+	Factory  common.Address
+	Salt     *big.Int
+	Deployed bool
+}
+
+// UserOperation is an ERC-4337 v0.7 user operation in its unpacked RPC form.
+type UserOperation struct {
+	Sender                        common.Address
+	Nonce                         *big.Int
+	Factory                       *common.Address // nil once the account is deployed
+	FactoryData                   []byte
+	CallData                      []byte
+	CallGasLimit                  *big.Int
+	VerificationGasLimit          *big.Int
+	PreVerificationGas            *big.Int
+	MaxFeePerGas                  *big.Int
+	MaxPriorityFeePerGas          *big.Int
+	Paymaster                     *common.Address
+	PaymasterVerificationGasLimit *big.Int
+	PaymasterPostOpGasLimit       *big.Int
+	PaymasterData                 []byte
+	Signature                     []byte
+}
+
+// -------------------------------
+// 🏗️ Smart Account Address
+// -------------------------------
+
+// GetSmartAccount returns the counterfactual address of the SimpleAccount
+// for owner and salt, and whether it has been deployed yet.
+func GetSmartAccount(client *ethclient.Client, owner common.Address, salt *big.Int) SmartAccount {
+	values, err := callView(client, SimpleAccountFactoryV07, smartAccountABI, "getAddress", owner, salt)
+	if err != nil {
+		log.Fatalf("❌ Failed to get smart account address: %v", err)
+	}
+	address := values[0].(common.Address)
+
+	code, err := client.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		log.Fatalf("❌ Failed to get smart account code: %v", err)
+	}
+	return SmartAccount{
+		Address:  address,
+		Owner:    owner,
+		Factory:  SimpleAccountFactoryV07,
+		Salt:     salt,
+		Deployed: len(code) > 0,
+	}
+}
+
+// -------------------------------
+// 🧱 Build User Operation
+// -------------------------------
+
+// BuildUserOperation prepares an unsigned operation executing a call from
+// the smart account. Gas limits are left for EstimateUserOperationGas.
+func BuildUserOperation(client *ethclient.Client, account SmartAccount, to common.Address, value *big.Int, data []byte) *UserOperation {
+	ctx := context.Background()
+	values, err := callView(client, EntryPointV07, smartAccountABI, "getNonce", account.Address, big.NewInt(0))
+	if err != nil {
+		log.Fatalf("❌ Failed to get account nonce: %v", err)
+	}
+
+	callData, err := smartAccountABI.Pack("execute", to, value, data)
+	if err != nil {
+		log.Fatalf("❌ Failed to encode execute: %v", err)
+	}
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		log.Fatalf("❌ Failed to get latest header: %v", err)
+	}
+	var tip, maxFee *big.Int
+	if header.BaseFee != nil {
+		tip, err = client.SuggestGasTipCap(ctx)
+		if err != nil {
+			log.Fatalf("❌ Failed to suggest gas tip: %v", err)
+		}
+		maxFee = new(big.Int).Add(tip, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	} else {
+		// Chains without EIP-1559 charge a single gas price, which the
+		// EntryPoint sees as maxFeePerGas == maxPriorityFeePerGas
+		maxFee, err = client.SuggestGasPrice(ctx)
+		if err != nil {
+			log.Fatalf("❌ Failed to suggest gas price: %v", err)
+		}
+		tip = new(big.Int).Set(maxFee)
+	}
+
+	op := &UserOperation{
+		Sender:               account.Address,
+		Nonce:                values[0].(*big.Int),
+		CallData:             callData,
+		CallGasLimit:         new(big.Int),
+		VerificationGasLimit: new(big.Int),
+		PreVerificationGas:   new(big.Int),
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: tip,
+		Signature:            simpleAccountDummySignature,
+	}
+	if !account.Deployed {
+		factoryData, err := smartAccountABI.Pack("createAccount", account.Owner, account.Salt)
+		if err != nil {
+			log.Fatalf("❌ Failed to encode createAccount: %v", err)
+		}
+		op.Factory = &account.Factory
+		op.FactoryData = factoryData
+	}
+	return op
+}
+
+// Hash returns the v0.7 userOpHash that the account owner signs.
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	var initCode []byte
+	if op.Factory != nil {
+		initCode = append(op.Factory.Bytes(), op.FactoryData...)
+	}
+	var paymasterAndData []byte
+	if op.Paymaster != nil {
+		paymasterAndData = append(paymasterAndData, op.Paymaster.Bytes()...)
+		paymasterAndData = append(paymasterAndData, common.LeftPadBytes(bigOrZero(op.PaymasterVerificationGasLimit).Bytes(), 16)...)
+		paymasterAndData = append(paymasterAndData, common.LeftPadBytes(bigOrZero(op.PaymasterPostOpGasLimit).Bytes(), 16)...)
+		paymasterAndData = append(paymasterAndData, op.PaymasterData...)
+	}
+
+	packed, err := abiArguments("address", "uint256", "bytes32", "bytes32", "bytes32", "uint256", "bytes32", "bytes32").Pack(
+		op.Sender,
+		op.Nonce,
+		crypto.Keccak256Hash(initCode),
+		crypto.Keccak256Hash(op.CallData),
+		packUint128Pair(op.VerificationGasLimit, op.CallGasLimit),
+		op.PreVerificationGas,
+		packUint128Pair(op.MaxPriorityFeePerGas, op.MaxFeePerGas),
+		crypto.Keccak256Hash(paymasterAndData),
+	)
+	if err != nil {
+		log.Fatalf("❌ Failed to pack user operation: %v", err)
+	}
+	encoded, err := abiArguments("bytes32", "address", "uint256").Pack(crypto.Keccak256Hash(packed), entryPoint, chainID)
+	if err != nil {
+		log.Fatalf("❌ Failed to encode user operation hash: %v", err)
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// SignUserOperation signs the operation for a SimpleAccount, which expects
+// an EIP-191 personal signature over the userOpHash.
+func SignUserOperation(op *UserOperation, privateKey *ecdsa.PrivateKey, chainID *big.Int) {
+	hash := op.Hash(EntryPointV07, chainID)
+	sig, err := crypto.Sign(accounts.TextHash(hash.Bytes()), privateKey)
+	if err != nil {
+		log.Fatalf("❌ Failed to sign user operation: %v", err)
+	}
+	sig[64] += 27
+	op.Signature = sig
+}
+
+// -------------------------------
+// 📮 Bundler RPC
+// -------------------------------
+
+// ConnectBundler connects to an ERC-4337 bundler JSON-RPC endpoint.
+func ConnectBundler(bundlerURL string) *rpc.Client {
+	bundler, err := rpc.Dial(bundlerURL)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to bundler: %v", err)
+	}
+	return bundler
+}
+
+// EstimateUserOperationGas fills the operation's gas limits from
+// eth_estimateUserOperationGas.
+func EstimateUserOperationGas(bundler *rpc.Client, op *UserOperation) error {
+	var estimate struct {
+		PreVerificationGas            *hexutil.Big `json:"preVerificationGas"`
+		VerificationGasLimit          *hexutil.Big `json:"verificationGasLimit"`
+		CallGasLimit                  *hexutil.Big `json:"callGasLimit"`
+		PaymasterVerificationGasLimit *hexutil.Big `json:"paymasterVerificationGasLimit"`
+		PaymasterPostOpGasLimit       *hexutil.Big `json:"paymasterPostOpGasLimit"`
+	}
+	err := bundler.CallContext(context.Background(), &estimate, "eth_estimateUserOperationGas", op.rpcFields(), EntryPointV07)
+	if err != nil {
+		return err
+	}
+	if estimate.PreVerificationGas == nil || estimate.VerificationGasLimit == nil || estimate.CallGasLimit == nil {
+		return fmt.Errorf("incomplete gas estimate from bundler")
+	}
+	op.PreVerificationGas = estimate.PreVerificationGas.ToInt()
+	op.VerificationGasLimit = estimate.VerificationGasLimit.ToInt()
+	op.CallGasLimit = estimate.CallGasLimit.ToInt()
+	if op.Paymaster != nil && estimate.PaymasterVerificationGasLimit != nil {
+		op.PaymasterVerificationGasLimit = estimate.PaymasterVerificationGasLimit.ToInt()
+	}
+	if op.Paymaster != nil && estimate.PaymasterPostOpGasLimit != nil {
+		op.PaymasterPostOpGasLimit = estimate.PaymasterPostOpGasLimit.ToInt()
+	}
+	return nil
+}
+
+// SendUserOperation submits a signed operation and returns its userOpHash.
+func SendUserOperation(bundler *rpc.Client, op *UserOperation) (common.Hash, error) {
+	var hash common.Hash
+	err := bundler.CallContext(context.Background(), &hash, "eth_sendUserOperation", op.rpcFields(), EntryPointV07)
+	return hash, err
+}
+
+// userOpPollInterval is how often WaitForUserOperation polls the bundler.
+var userOpPollInterval = 2 * time.Second
+
+// userOpTimeout bounds how long SendSmartAccountTransaction waits for a
+// bundler to include an operation.
+const userOpTimeout = 5 * time.Minute
+
+// WaitForUserOperation polls eth_getUserOperationReceipt until the
+// operation is included and returns the bundle transaction hash. It gives
+// up when ctx is done.
+func WaitForUserOperation(ctx context.Context, bundler *rpc.Client, userOpHash common.Hash) (txHash common.Hash, success bool, err error) {
+	ticker := time.NewTicker(userOpPollInterval)
+	defer ticker.Stop()
+	for {
+		var receipt *struct {
+			Success bool `json:"success"`
+			Receipt struct {
+				TransactionHash common.Hash `json:"transactionHash"`
+			} `json:"receipt"`
+		}
+		if err := bundler.CallContext(ctx, &receipt, "eth_getUserOperationReceipt", userOpHash); err != nil {
+			return common.Hash{}, false, err
+		}
+		if receipt != nil {
+			return receipt.Receipt.TransactionHash, receipt.Success, nil
+		}
+		select {
+		case <-ctx.Done():
+			return common.Hash{}, false, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// -------------------------------
+// 🚀 Send from Smart Account
+// -------------------------------
+
+// SendSmartAccountTransaction sends amountEther to toAddress from the
+// owner's SimpleAccount (deploying it on first use) through a bundler.
+func SendSmartAccountTransaction(client *ethclient.Client, bundlerURL string, privateKey *ecdsa.PrivateKey, salt *big.Int, toAddress common.Address, amountEther float64) {
+	owner := crypto.PubkeyToAddress(privateKey.PublicKey)
+	account := GetSmartAccount(client, owner, salt)
+	fmt.Printf("🏗️ Smart account %s (deployed: %t)\n", account.Address.Hex(), account.Deployed)
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Failed to get chain ID: %v", err)
+	}
+	checkSigningChain(client, chainID.Int64())
+
+	bundler := ConnectBundler(bundlerURL)
+	defer bundler.Close()
+
+	op := BuildUserOperation(client, account, toAddress, EtherToWei(amountEther), nil)
+	if err := EstimateUserOperationGas(bundler, op); err != nil {
+		log.Fatalf("❌ Failed to estimate user operation gas: %s", decodeRevert(err, nil))
+	}
+	SignUserOperation(op, privateKey, chainID)
+
+	userOpHash, err := SendUserOperation(bundler, op)
+	if err != nil {
+		log.Fatalf("❌ Failed to send user operation: %s", decodeRevert(err, nil))
+	}
+	fmt.Println("📮 User operation submitted:", userOpHash.Hex())
+
+	ctx, cancel := context.WithTimeout(context.Background(), userOpTimeout)
+	defer cancel()
+	txHash, success, err := WaitForUserOperation(ctx, bundler, userOpHash)
+	if err != nil {
+		log.Fatalf("❌ Failed to get user operation receipt: %v", err)
+	}
+	if !success {
+		log.Fatalf("❌ User operation reverted in %s", txHash.Hex())
+	}
+	fmt.Printf("✅ User operation executed!\n🔗 Hash: %s\n", txHash.Hex())
+}
+
+// rpcFields encodes the operation as the bundler RPC expects: hex
+// quantities, with factory and paymaster fields omitted when unused.
+func (op *UserOperation) rpcFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"sender":               op.Sender,
+		"nonce":                (*hexutil.Big)(op.Nonce),
+		"callData":             hexutil.Bytes(op.CallData),
+		"callGasLimit":         (*hexutil.Big)(op.CallGasLimit),
+		"verificationGasLimit": (*hexutil.Big)(op.VerificationGasLimit),
+		"preVerificationGas":   (*hexutil.Big)(op.PreVerificationGas),
+		"maxFeePerGas":         (*hexutil.Big)(op.MaxFeePerGas),
+		"maxPriorityFeePerGas": (*hexutil.Big)(op.MaxPriorityFeePerGas),
+		"signature":            hexutil.Bytes(op.Signature),
+	}
+	if op.Factory != nil {
+		fields["factory"] = op.Factory
+		fields["factoryData"] = hexutil.Bytes(op.FactoryData)
+	}
+	if op.Paymaster != nil {
+		fields["paymaster"] = op.Paymaster
+		fields["paymasterVerificationGasLimit"] = (*hexutil.Big)(bigOrZero(op.PaymasterVerificationGasLimit))
+		fields["paymasterPostOpGasLimit"] = (*hexutil.Big)(bigOrZero(op.PaymasterPostOpGasLimit))
+		fields["paymasterData"] = hexutil.Bytes(op.PaymasterData)
+	}
+	return fields
+}
+
+// packUint128Pair packs two 128-bit values into one word, high first.
+func packUint128Pair(high, low *big.Int) [32]byte {
+	var word [32]byte
+	bigOrZero(high).FillBytes(word[:16])
+	bigOrZero(low).FillBytes(word[16:])
+	return word
+}
+
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value
+}
+
+// abiArguments builds unnamed ABI arguments for abi.encode-style packing.
+func abiArguments(types ...string) abi.Arguments {
+	args := make(abi.Arguments, len(types))
+	for i, typeName := range types {
+		typ, err := abi.NewType(typeName, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return args
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// rpcStub is a JSON-RPC endpoint answering from per-method handlers, used
+// both as a node and as a bundler. Unknown methods fail like on a real node.
+type rpcStub struct {
+	mu      sync.Mutex
+	methods map[string]func(params []json.RawMessage) (interface{}, error)
+	calls   map[string]int
+}
+
+func newRPCStub(t *testing.T) (*rpcStub, string) {
+	stub := &rpcStub{
+		methods: make(map[string]func([]json.RawMessage) (interface{}, error)),
+		calls:   make(map[string]int),
+	}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, server.URL
+}
+
+func (s *rpcStub) handle(method string, handler func(params []json.RawMessage) (interface{}, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[method] = handler
+}
+
+func (s *rpcStub) callCount(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *rpcStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method]++
+	handler, ok := s.methods[req.Method]
+	s.mu.Unlock()
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if !ok {
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method " + req.Method + " not found"}
+	} else if result, err := handler(req.Params); err != nil {
+		resp["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// returns makes a handler with a fixed result.
+func returns(result interface{}) func([]json.RawMessage) (interface{}, error) {
+	return func([]json.RawMessage) (interface{}, error) { return result, nil }
+}
+
+func dialStub(t *testing.T, url string) *ethclient.Client {
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// stubUserOpNode serves what BuildUserOperation reads from a node.
+func stubUserOpNode(t *testing.T, baseFee *big.Int) (*rpcStub, *ethclient.Client) {
+	stub, url := newRPCStub(t)
+	nonce, err := smartAccountABI.Methods["getNonce"].Outputs.Pack(big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub.handle("eth_call", returns(hexutil.Bytes(nonce)))
+	stub.handle("eth_getBlockByNumber", returns(&types.Header{
+		Number:     big.NewInt(100),
+		Difficulty: big.NewInt(1),
+		BaseFee:    baseFee,
+	}))
+	stub.handle("eth_gasPrice", returns((*hexutil.Big)(big.NewInt(5e9))))
+	if baseFee != nil {
+		stub.handle("eth_maxPriorityFeePerGas", returns((*hexutil.Big)(big.NewInt(1e9))))
+	}
+	return stub, dialStub(t, url)
+}
+
+func testSmartAccount(owner common.Address, deployed bool) SmartAccount {
+	return SmartAccount{
+		Address:  common.HexToAddress("0x00000000000000000000000000000000000a11ce"),
+		Owner:    owner,
+		Factory:  SimpleAccountFactoryV07,
+		Salt:     big.NewInt(0),
+		Deployed: deployed,
+	}
+}
+
+func TestBuildUserOperationEIP1559(t *testing.T) {
+	_, client := stubUserOpNode(t, big.NewInt(10e9))
+	op := BuildUserOperation(client, testSmartAccount(common.Address{1}, true), common.Address{2}, big.NewInt(1), nil)
+
+	if op.Nonce.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("nonce = %s, want 3", op.Nonce)
+	}
+	if op.MaxPriorityFeePerGas.Cmp(big.NewInt(1e9)) != 0 || op.MaxFeePerGas.Cmp(big.NewInt(21e9)) != 0 {
+		t.Errorf("fees = %s/%s, want tip 1 gwei and max 21 gwei", op.MaxPriorityFeePerGas, op.MaxFeePerGas)
+	}
+	if op.Factory != nil {
+		t.Error("deployed account got a factory")
+	}
+}
+
+func TestBuildUserOperationWithoutBaseFee(t *testing.T) {
+	// Chains like Ethereum Classic have no base fee and no
+	// eth_maxPriorityFeePerGas; the gas price is used for both fees.
+	_, client := stubUserOpNode(t, nil)
+	op := BuildUserOperation(client, testSmartAccount(common.Address{1}, false), common.Address{2}, big.NewInt(1), nil)
+
+	if op.MaxPriorityFeePerGas.Cmp(big.NewInt(5e9)) != 0 || op.MaxFeePerGas.Cmp(big.NewInt(5e9)) != 0 {
+		t.Errorf("fees = %s/%s, want the 5 gwei gas price for both", op.MaxPriorityFeePerGas, op.MaxFeePerGas)
+	}
+	if op.Factory == nil || *op.Factory != SimpleAccountFactoryV07 || len(op.FactoryData) == 0 {
+		t.Error("undeployed account is missing its factory")
+	}
+}
+
+func TestUserOperationBundler(t *testing.T) {
+	defer func(interval time.Duration) { userOpPollInterval = interval }(userOpPollInterval)
+	userOpPollInterval = time.Millisecond
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(11155111)
+	userOpHash := common.HexToHash("0x01")
+	bundleHash := common.HexToHash("0x02")
+
+	stub, url := newRPCStub(t)
+	var sent map[string]interface{}
+	entryPointParam := func(params []json.RawMessage) error {
+		var entryPoint common.Address
+		if len(params) != 2 || json.Unmarshal(params[1], &entryPoint) != nil || entryPoint != EntryPointV07 {
+			return errors.New("expected the v0.7 EntryPoint as second parameter")
+		}
+		return nil
+	}
+	stub.handle("eth_estimateUserOperationGas", func(params []json.RawMessage) (interface{}, error) {
+		if err := entryPointParam(params); err != nil {
+			return nil, err
+		}
+		return map[string]string{"preVerificationGas": "0xc350", "verificationGasLimit": "0x186a0", "callGasLimit": "0x7530"}, nil
+	})
+	stub.handle("eth_sendUserOperation", func(params []json.RawMessage) (interface{}, error) {
+		if err := entryPointParam(params); err != nil {
+			return nil, err
+		}
+		return userOpHash, json.Unmarshal(params[0], &sent)
+	})
+	polls := 0
+	stub.handle("eth_getUserOperationReceipt", func([]json.RawMessage) (interface{}, error) {
+		if polls++; polls < 3 {
+			return nil, nil
+		}
+		return map[string]interface{}{"success": true, "receipt": map[string]interface{}{"transactionHash": bundleHash}}, nil
+	})
+
+	bundler := ConnectBundler(url)
+	defer bundler.Close()
+	op := &UserOperation{
+		Sender:               common.Address{1},
+		Nonce:                big.NewInt(0),
+		MaxFeePerGas:         big.NewInt(2e9),
+		MaxPriorityFeePerGas: big.NewInt(1e9),
+		Signature:            simpleAccountDummySignature,
+	}
+	if err := EstimateUserOperationGas(bundler, op); err != nil {
+		t.Fatal(err)
+	}
+	if op.PreVerificationGas.Int64() != 50000 || op.VerificationGasLimit.Int64() != 100000 || op.CallGasLimit.Int64() != 30000 {
+		t.Errorf("gas limits = %s/%s/%s", op.PreVerificationGas, op.VerificationGasLimit, op.CallGasLimit)
+	}
+
+	SignUserOperation(op, key, chainID)
+	pubKey, err := crypto.SigToPub(accounts.TextHash(op.Hash(EntryPointV07, chainID).Bytes()), append(op.Signature[:64:64], op.Signature[64]-27))
+	if err != nil || crypto.PubkeyToAddress(*pubKey) != owner {
+		t.Fatalf("signature does not recover to the owner: %v", err)
+	}
+
+	hash, err := SendUserOperation(bundler, op)
+	if err != nil || hash != userOpHash {
+		t.Fatalf("SendUserOperation = %s, %v", hash.Hex(), err)
+	}
+	if sent["signature"] != hexutil.Encode(op.Signature) || sent["callGasLimit"] != "0x7530" {
+		t.Errorf("bundler received %v", sent)
+	}
+	if _, ok := sent["factory"]; ok {
+		t.Error("factory sent for a deployed account")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	txHash, success, err := WaitForUserOperation(ctx, bundler, userOpHash)
+	if err != nil || !success || txHash != bundleHash {
+		t.Errorf("WaitForUserOperation = %s, %t, %v", txHash.Hex(), success, err)
+	}
+	if n := stub.callCount("eth_getUserOperationReceipt"); n != 3 {
+		t.Errorf("polled %d times, want 3", n)
+	}
+}
+
+func TestWaitForUserOperationTimeout(t *testing.T) {
+	defer func(interval time.Duration) { userOpPollInterval = interval }(userOpPollInterval)
+	userOpPollInterval = time.Millisecond
+
+	stub, url := newRPCStub(t)
+	stub.handle("eth_getUserOperationReceipt", returns(nil))
+	bundler := ConnectBundler(url)
+	defer bundler.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := WaitForUserOperation(ctx, bundler, common.HexToHash("0x01")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context deadline", err)
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// LitecoinBalance holds an address balance in litoshis.
+type LitecoinBalance struct {
+	Confirmed   int64
+	Unconfirmed int64
+}
+
+// LitecoinUTXO is an unspent output as reported by an indexer.
+type LitecoinUTXO struct {
+	TxID          string
+	Vout          uint32
+	Value         int64
+	ScriptPubKey  string // hex, empty when the indexer does not report it
+	Confirmations int64
+}
+
+// LitecoinBackend is a Litecoin indexer queried over REST.
+type LitecoinBackend interface {
+	GetBalance(address string) (LitecoinBalance, error)
+	ListUnspent(address string) ([]LitecoinUTXO, error)
+	// EstimateFeeRate returns a fee rate in litoshi/vB.
+	EstimateFeeRate() (int64, error)
+	// Broadcast publishes a signed transaction and returns its txid.
+	Broadcast(tx *wire.MsgTx) (string, error)
+}
+
+// BlockCypherBackend queries the BlockCypher REST API.
+type BlockCypherBackend struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+	// MaxRetries bounds retries of rate-limited (HTTP 429) requests.
+	MaxRetries int
+}
+
+// NewBlockCypherBackend creates a BlockCypher backend with default retry
+// settings.
+func NewBlockCypherBackend(baseURL, token string) *BlockCypherBackend {
+	return &BlockCypherBackend{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: 3,
+	}
+}
+
+// blockCypherPageLimit is the largest page BlockCypher returns.
+const blockCypherPageLimit = 2000
+
+type blockCypherTxRef struct {
+	TxHash        string `json:"tx_hash"`
+	BlockHeight   int64  `json:"block_height"`
+	TxOutputN     uint32 `json:"tx_output_n"`
+	Value         int64  `json:"value"`
+	Confirmations int64  `json:"confirmations"`
+	Script        string `json:"script"`
+}
+
+// GetBalance returns the confirmed and unconfirmed balance of address.
+func (b *BlockCypherBackend) GetBalance(address string) (LitecoinBalance, error) {
+	var resp struct {
+		Balance            int64 `json:"balance"`
+		UnconfirmedBalance int64 `json:"unconfirmed_balance"`
+	}
+	if err := b.get(fmt.Sprintf("/addrs/%s/balance", address), nil, &resp); err != nil {
+		return LitecoinBalance{}, err
+	}
+	return LitecoinBalance{Confirmed: resp.Balance, Unconfirmed: resp.UnconfirmedBalance}, nil
+}
+
+// ListUnspent returns all unspent outputs of address, following
+// BlockCypher's pagination.
+func (b *BlockCypherBackend) ListUnspent(address string) ([]LitecoinUTXO, error) {
+	var utxos []LitecoinUTXO
+	seen := make(map[string]bool)
+	params := url.Values{
+		"unspentOnly":   {"true"},
+		"includeScript": {"true"},
+		"limit":         {strconv.Itoa(blockCypherPageLimit)},
+	}
+	for page := 0; ; page++ {
+		var resp struct {
+			TxRefs            []blockCypherTxRef `json:"txrefs"`
+			UnconfirmedTxRefs []blockCypherTxRef `json:"unconfirmed_txrefs"`
+			HasMore           bool               `json:"hasMore"`
+		}
+		if err := b.get("/addrs/"+address, params, &resp); err != nil {
+			return nil, err
+		}
+
+		refs := resp.TxRefs
+		if page == 0 {
+			refs = append(refs, resp.UnconfirmedTxRefs...)
+		}
+		added := 0
+		lowest := int64(-1)
+		for _, ref := range refs {
+			if ref.BlockHeight > 0 && (lowest < 0 || ref.BlockHeight < lowest) {
+				lowest = ref.BlockHeight
+			}
+			outpoint := fmt.Sprintf("%s:%d", ref.TxHash, ref.TxOutputN)
+			if seen[outpoint] {
+				continue
+			}
+			seen[outpoint] = true
+			added++
+			utxos = append(utxos, LitecoinUTXO{
+				TxID:          ref.TxHash,
+				Vout:          ref.TxOutputN,
+				Value:         ref.Value,
+				ScriptPubKey:  ref.Script,
+				Confirmations: ref.Confirmations,
+			})
+		}
+
+		if !resp.HasMore || lowest < 0 {
+			return utxos, nil
+		}
+		// Pages are cut by block height, and the last height of a page may
+		// continue on the next one, so it is requested again and repeated
+		// outputs are skipped. A page with nothing new means a single
+		// height holds more outputs than a page, which cannot be paged.
+		if page > 0 && added == 0 {
+			return nil, fmt.Errorf("more than %d unspent outputs at block %d, cannot page past them", blockCypherPageLimit, lowest)
+		}
+		params.Set("before", strconv.FormatInt(lowest+1, 10))
+	}
+}
+
+// EstimateFeeRate returns BlockCypher's medium fee estimate in litoshi/vB.
+func (b *BlockCypherBackend) EstimateFeeRate() (int64, error) {
+	var resp struct {
+		MediumFeePerKB int64 `json:"medium_fee_per_kb"`
+	}
+	if err := b.get("", nil, &resp); err != nil {
+		return 0, err
+	}
+	if resp.MediumFeePerKB <= 0 {
+		return 0, fmt.Errorf("no fee estimate available")
+	}
+	return (resp.MediumFeePerKB + 999) / 1000, nil
+}
+
+// Broadcast pushes a signed transaction through /txs/push.
+func (b *BlockCypherBackend) Broadcast(tx *wire.MsgTx) (string, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(map[string]string{"tx": hex.EncodeToString(buf.Bytes())})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := b.BaseURL + "/txs/push"
+	if b.Token != "" {
+		endpoint += "?" + url.Values{"token": {b.Token}}.Encode()
+	}
+	body, err := b.do(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Tx struct {
+			Hash string `json:"hash"`
+		} `json:"tx"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Tx.Hash == "" {
+		return tx.TxHash().String(), nil
+	}
+	return resp.Tx.Hash, nil
+}
+
+// get performs a GET request, adding the API token and retrying with
+// backoff while BlockCypher answers 429 Too Many Requests.
+func (b *BlockCypherBackend) get(path string, params url.Values, result interface{}) error {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	if b.Token != "" {
+		query.Set("token", b.Token)
+	}
+	endpoint := b.BaseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	body, err := b.do(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
+
+func (b *BlockCypherBackend) do(newRequest func() (*http.Request, error)) ([]byte, error) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := b.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < b.MaxRetries {
+			wait := backoff
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				wait = time.Duration(seconds) * time.Second
+			}
+			time.Sleep(wait)
+			backoff *= 2
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			var apiErr struct {
+				Error string `json:"error"`
+			}
+			if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+				return nil, fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+			}
+			return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		return body, nil
+	}
+}
+
+// EsploraLitecoinBackend queries an Esplora-compatible Litecoin indexer
+// such as https://litecoinspace.org/api.
+type EsploraLitecoinBackend struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewEsploraLitecoinBackend creates an Esplora backend for baseURL.
+func NewEsploraLitecoinBackend(baseURL string) *EsploraLitecoinBackend {
+	return &EsploraLitecoinBackend{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetBalance returns the confirmed and mempool balance of address.
+func (e *EsploraLitecoinBackend) GetBalance(address string) (LitecoinBalance, error) {
+	type stats struct {
+		Funded int64 `json:"funded_txo_sum"`
+		Spent  int64 `json:"spent_txo_sum"`
+	}
+	var resp struct {
+		ChainStats   stats `json:"chain_stats"`
+		MempoolStats stats `json:"mempool_stats"`
+	}
+	if err := e.get("/address/"+address, &resp); err != nil {
+		return LitecoinBalance{}, err
+	}
+	return LitecoinBalance{
+		Confirmed:   resp.ChainStats.Funded - resp.ChainStats.Spent,
+		Unconfirmed: resp.MempoolStats.Funded - resp.MempoolStats.Spent,
+	}, nil
+}
+
+// ListUnspent returns the unspent outputs of address.
+func (e *EsploraLitecoinBackend) ListUnspent(address string) ([]LitecoinUTXO, error) {
+	var resp []struct {
+		TxID   string `json:"txid"`
+		Vout   uint32 `json:"vout"`
+		Value  int64  `json:"value"`
+		Status struct {
+			Confirmed bool `json:"confirmed"`
+		} `json:"status"`
+	}
+	if err := e.get("/address/"+address+"/utxo", &resp); err != nil {
+		return nil, err
+	}
+	utxos := make([]LitecoinUTXO, 0, len(resp))
+	for _, u := range resp {
+		utxo := LitecoinUTXO{TxID: u.TxID, Vout: u.Vout, Value: u.Value}
+		if u.Status.Confirmed {
+			utxo.Confirmations = 1
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+// EstimateFeeRate returns the six-block fee estimate in litoshi/vB.
+func (e *EsploraLitecoinBackend) EstimateFeeRate() (int64, error) {
+	var estimates map[string]float64
+	if err := e.get("/fee-estimates", &estimates); err != nil {
+		return 0, err
+	}
+	rate, ok := estimates["6"]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no fee estimate available")
+	}
+	return int64(rate + 0.999), nil
+}
+
+// Broadcast posts the raw transaction hex to /tx.
+func (e *EsploraLitecoinBackend) Broadcast(tx *wire.MsgTx) (string, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return "", err
+	}
+	resp, err := e.HTTPClient.Post(e.BaseURL+"/tx", "text/plain", strings.NewReader(hex.EncodeToString(buf.Bytes())))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func (e *EsploraLitecoinBackend) get(path string, result interface{}) error {
+	resp, err := e.HTTPClient.Get(e.BaseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, result)
+}
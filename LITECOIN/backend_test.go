@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// blockCypherStub serves /addrs/{address} like BlockCypher: unspent
+// outputs ordered by height, pageSize per page, cut by the before filter.
+type blockCypherStub struct {
+	t           *testing.T
+	refs        []blockCypherTxRef
+	unconfirmed []blockCypherTxRef
+	pageSize    int
+	requests    int
+}
+
+func (s *blockCypherStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	if r.URL.Query().Get("token") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid token"})
+		return
+	}
+
+	refs := append([]blockCypherTxRef{}, s.refs...)
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].BlockHeight > refs[j].BlockHeight })
+	if before := r.URL.Query().Get("before"); before != "" {
+		height, _ := strconv.ParseInt(before, 10, 64)
+		filtered := refs[:0]
+		for _, ref := range refs {
+			if ref.BlockHeight < height {
+				filtered = append(filtered, ref)
+			}
+		}
+		refs = filtered
+	}
+	resp := map[string]interface{}{"hasMore": len(refs) > s.pageSize}
+	if len(refs) > s.pageSize {
+		refs = refs[:s.pageSize]
+	}
+	resp["txrefs"] = refs
+	if r.URL.Query().Get("before") == "" {
+		resp["unconfirmed_txrefs"] = s.unconfirmed
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newBlockCypherStub(t *testing.T, handler http.Handler) *BlockCypherBackend {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewBlockCypherBackend(server.URL, "secret")
+}
+
+func txRef(height int64, n int) blockCypherTxRef {
+	return blockCypherTxRef{
+		TxHash:        fmt.Sprintf("%064x", height*1000+int64(n)),
+		BlockHeight:   height,
+		TxOutputN:     uint32(n),
+		Value:         1000,
+		Confirmations: 1,
+	}
+}
+
+func TestBlockCypherListUnspentPagination(t *testing.T) {
+	// Heights 105..100 with three outputs each; pages of 4 cut through
+	// heights, so outputs at a page's last height continue on the next.
+	stub := &blockCypherStub{t: t, pageSize: 4}
+	for height := int64(105); height >= 100; height-- {
+		for n := 0; n < 3; n++ {
+			stub.refs = append(stub.refs, txRef(height, n))
+		}
+	}
+	stub.unconfirmed = []blockCypherTxRef{{TxHash: strings.Repeat("ff", 32), BlockHeight: -1, Value: 500}}
+
+	utxos, err := newBlockCypherStub(t, stub).ListUnspent("LTCaddr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != len(stub.refs)+1 {
+		t.Fatalf("got %d UTXOs, want %d", len(utxos), len(stub.refs)+1)
+	}
+	seen := make(map[string]bool)
+	for _, utxo := range utxos {
+		key := fmt.Sprintf("%s:%d", utxo.TxID, utxo.Vout)
+		if seen[key] {
+			t.Errorf("UTXO %s returned twice", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestBlockCypherListUnspentStuckPage(t *testing.T) {
+	// More outputs at one height than fit in a page cannot be paged
+	// with before, which must fail instead of looping.
+	stub := &blockCypherStub{t: t, pageSize: 3}
+	for n := 0; n < 5; n++ {
+		stub.refs = append(stub.refs, txRef(100, n))
+	}
+
+	_, err := newBlockCypherStub(t, stub).ListUnspent("LTCaddr")
+	if err == nil || !strings.Contains(err.Error(), "block 100") {
+		t.Fatalf("err = %v, want an error about block 100", err)
+	}
+	if stub.requests != 2 {
+		t.Errorf("made %d requests, want 2", stub.requests)
+	}
+}
+
+func TestBlockCypherRetriesRateLimit(t *testing.T) {
+	attempts := 0
+	backend := newBlockCypherStub(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"balance": 150000, "unconfirmed_balance": -2000}`)
+	}))
+
+	balance, err := backend.GetBalance("LTCaddr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || balance.Confirmed != 150000 || balance.Unconfirmed != -2000 {
+		t.Errorf("balance = %+v after %d attempts, want 150000/-2000 after 3", balance, attempts)
+	}
+}
+
+func TestBlockCypherGivesUpOnRateLimit(t *testing.T) {
+	attempts := 0
+	backend := newBlockCypherStub(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": "Limits reached."}`)
+	}))
+	backend.MaxRetries = 1
+
+	_, err := backend.GetBalance("LTCaddr")
+	if err == nil || !strings.Contains(err.Error(), "Limits reached.") {
+		t.Errorf("err = %v, want the rate limit error", err)
+	}
+	if attempts != 2 {
+		t.Errorf("made %d attempts, want 2", attempts)
+	}
+}
+
+func TestBlockCypherToken(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+
+	var paths []string
+	backend := newBlockCypherStub(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/txs/push":
+			fmt.Fprintf(w, `{"tx": {"hash": %q}}`, tx.TxHash())
+		default:
+			fmt.Fprint(w, `{"medium_fee_per_kb": 12500}`)
+		}
+	}))
+
+	rate, err := backend.EstimateFeeRate()
+	if err != nil || rate != 13 {
+		t.Errorf("EstimateFeeRate = %d, %v, want 13", rate, err)
+	}
+	txid, err := backend.Broadcast(tx)
+	if err != nil || txid != tx.TxHash().String() {
+		t.Errorf("Broadcast = %s, %v, want %s", txid, err, tx.TxHash())
+	}
+	for _, path := range paths {
+		if !strings.Contains(path, "token=secret") {
+			t.Errorf("request %s does not carry the token", path)
+		}
+	}
+
+	backend.Token = ""
+	if _, err := backend.EstimateFeeRate(); err != nil {
+		t.Fatal(err)
+	}
+	if last := paths[len(paths)-1]; strings.Contains(last, "token") {
+		t.Errorf("request %s carries a token although none is set", last)
+	}
+}
+
+func TestBlockCypherInvalidToken(t *testing.T) {
+	backend := newBlockCypherStub(t, &blockCypherStub{t: t, pageSize: 10})
+	backend.Token = "wrong"
+	if _, err := backend.ListUnspent("LTCaddr"); err == nil || !strings.Contains(err.Error(), "invalid token") {
+		t.Errorf("err = %v, want the API's invalid token error", err)
+	}
+}
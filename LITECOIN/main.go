@@ -1,34 +1,24 @@
 package main
 
 import (
-	"encoding/hex"
 	"fmt"
 	"log"
+	"math"
 	"math/big"
+	"sort"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 )
 
-// ConnectLitecoinClient connects to a Litecoin network
-func ConnectLitecoinClient(rpcURL, user, pass string) *rpcclient.Client {
-	connCfg := &rpcclient.ConnConfig{
-		Host:         rpcURL,
-		User:         user,
-		Pass:         pass,
-		HTTPPostMode: true,
-		DisableTLS:   false, // Enable TLS for HTTPS
-	}
-	client, err := rpcclient.New(connCfg, nil)
-	if err != nil {
-		log.Fatalf("❌ Failed to connect to Litecoin network: %v", err)
-	}
-	return client
+// ConnectLitecoinClient connects to a BlockCypher Litecoin API such as
+// https://api.blockcypher.com/v1/ltc/main. The token is optional and raises
+// the rate limit.
+func ConnectLitecoinClient(apiURL, token string) LitecoinBackend {
+	return NewBlockCypherBackend(apiURL, token)
 }
 
 // CreateLitecoinAccount generates a new Litecoin account
@@ -74,98 +64,183 @@ func LoadLitecoinAccount(wifStr string, net *chaincfg.Params) (*btcutil.WIF, str
 	return wif, addr.EncodeAddress(), nil
 }
 
-// GetLitecoinBalance retrieves the balance of a Litecoin account
-func GetLitecoinBalance(client *rpcclient.Client, address string) *big.Float {
-	// Placeholder: returns 0 as accounts are new and public APIs like BlockCypher are used
-	// In a real implementation, use HTTP requests to query balance via the API
-	return big.NewFloat(0.0)
+// GetLitecoinBalance retrieves the confirmed and unconfirmed balance of a
+// Litecoin account in LTC
+func GetLitecoinBalance(backend LitecoinBackend, address string) (confirmed, unconfirmed *big.Float) {
+	balance, err := backend.GetBalance(address)
+	if err != nil {
+		log.Printf("❌ Failed to get balance: %v", err)
+		return big.NewFloat(0), big.NewFloat(0)
+	}
+	return SatoshisToLTC(balance.Confirmed), SatoshisToLTC(balance.Unconfirmed)
 }
 
-// SendLitecoinTransaction sends a Litecoin transaction
-func SendLitecoinTransaction(client *rpcclient.Client, wif *btcutil.WIF, toAddress string, amountBTC float64, net *chaincfg.Params) {
-	fromAddr, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(wif.PrivKey.PubKey().SerializeCompressed()), net)
+// SendLitecoinTransaction sends amountLTC to toAddress, spending the
+// P2PKH and P2WPKH (ltc1) outputs of the WIF and returning change to its
+// P2PKH address
+func SendLitecoinTransaction(backend LitecoinBackend, wif *btcutil.WIF, toAddress string, amountLTC float64, net *chaincfg.Params) {
+	fromAddr, _ := litecoinKeyAddresses(wif, net)
+	changeScript, err := txscript.PayToAddrScript(fromAddr)
 	if err != nil {
-		log.Fatalf("❌ Failed to create from address: %v", err)
+		log.Fatalf("❌ Failed to create change script: %v", err)
 	}
 
 	toAddr, err := btcutil.DecodeAddress(toAddress, net)
 	if err != nil {
 		log.Fatalf("❌ Invalid to address: %v", err)
 	}
+	pkScript, err := txscript.PayToAddrScript(toAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to create pkScript: %v", err)
+	}
 
-	amount := btcutil.Amount(amountBTC * 1e8) // Convert to satoshis
+	amount := LTCToSatoshis(amountLTC)
+	if amount < LitecoinDustLimit {
+		log.Fatalf("❌ Amount of %d litoshi is below the dust limit", amount)
+	}
 
-	// Get unspent outputs
-	utxos, err := client.ListUnspentMinMaxAddresses(1, 9999999, []btcutil.Address{fromAddr})
+	feeRate, err := backend.EstimateFeeRate()
 	if err != nil {
-		log.Fatalf("❌ Failed to list unspent: %v", err)
+		log.Printf("⚠️ Fee estimation unavailable (%v), using %d litoshi/vB", err, DefaultLitecoinFeeRate)
+		feeRate = DefaultLitecoinFeeRate
 	}
 
+	// Spend the largest outputs first to keep the input count low
+	utxos := listLitecoinInputs(backend, wif, net)
 	if len(utxos) == 0 {
 		log.Fatal("❌ No unspent outputs available")
 	}
+	sort.Slice(utxos, func(i, j int) bool { return utxos[i].Value > utxos[j].Value })
 
-	// Create transaction
-	tx := wire.NewMsgTx(wire.TxVersion)
-
-	totalInput := btcutil.Amount(0)
+	var selected []litecoinInput
+	var total, fee int64
 	for _, utxo := range utxos {
-		txid, err := hex.DecodeString(utxo.TxID)
-		if err != nil {
-			log.Fatalf("❌ Invalid txid: %v", err)
-		}
-		var hash chainhash.Hash
-		copy(hash[:], txid)
-		outPoint := wire.NewOutPoint(&hash, utxo.Vout)
-		txIn := wire.NewTxIn(outPoint, nil, nil)
-		tx.AddTxIn(txIn)
-		totalInput += btcutil.Amount(utxo.Amount * 1e8)
-		if totalInput >= amount+1000 { // + fee
+		selected = append(selected, utxo)
+		total += utxo.Value
+		fee = litecoinTxVSize(selected, pkScript, changeScript) * feeRate
+		if total >= amount+fee {
 			break
 		}
 	}
+	if total < amount+fee {
+		log.Fatalf("❌ Insufficient funds: have %d litoshi, need %d litoshi including fee", total, amount+fee)
+	}
 
-	if totalInput < amount+1000 {
-		log.Fatal("❌ Insufficient funds")
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for _, utxo := range selected {
+		tx.AddTxIn(wire.NewTxIn(utxo.OutPoint, nil, nil))
 	}
+	tx.AddTxOut(wire.NewTxOut(amount, pkScript))
 
-	// Add output
-	pkScript, err := txscript.PayToAddrScript(toAddr)
+	// Change below the dust limit is left to the miner
+	if change := total - amount - fee; change >= LitecoinDustLimit {
+		tx.AddTxOut(wire.NewTxOut(change, changeScript))
+	}
+
+	signLitecoinTx(tx, wif, selected)
+
+	txHash, err := backend.Broadcast(tx)
 	if err != nil {
-		log.Fatalf("❌ Failed to create pkScript: %v", err)
+		log.Fatalf("❌ Failed to send transaction: %v", err)
+	}
+
+	fmt.Printf("✅ Transaction sent successfully!\n🔗 TxID: %s\n", txHash)
+}
+
+// litecoinInput is a UTXO owned by the signing key
+type litecoinInput struct {
+	OutPoint      *wire.OutPoint
+	Value         int64
+	PkScript      []byte
+	Witness       bool
+	Confirmations int64
+}
+
+// litecoinKeyAddresses returns the P2PKH and P2WPKH addresses of a key. The
+// P2WPKH address is nil when net has no bech32 prefix.
+func litecoinKeyAddresses(wif *btcutil.WIF, net *chaincfg.Params) (*btcutil.AddressPubKeyHash, *btcutil.AddressWitnessPubKeyHash) {
+	pubKeyHash := btcutil.Hash160(wif.PrivKey.PubKey().SerializeCompressed())
+	legacy, err := btcutil.NewAddressPubKeyHash(pubKeyHash, net)
+	if err != nil {
+		log.Fatalf("❌ Failed to create address: %v", err)
+	}
+	if net.Bech32HRPSegwit == "" {
+		return legacy, nil
+	}
+	segwit, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, net)
+	if err != nil {
+		log.Fatalf("❌ Failed to create ltc1 address: %v", err)
+	}
+	return legacy, segwit
+}
+
+// listLitecoinInputs collects the UTXOs of both addresses of a key, which
+// may be none
+func listLitecoinInputs(backend LitecoinBackend, wif *btcutil.WIF, net *chaincfg.Params) []litecoinInput {
+	legacy, segwit := litecoinKeyAddresses(wif, net)
+	addrs := []btcutil.Address{legacy}
+	if segwit != nil {
+		addrs = append(addrs, segwit)
 	}
-	tx.AddTxOut(wire.NewTxOut(int64(amount), pkScript))
 
-	// Change output
-	change := totalInput - amount - 1000
-	if change > 0 {
-		changeScript, err := txscript.PayToAddrScript(fromAddr)
+	var inputs []litecoinInput
+	for _, addr := range addrs {
+		pkScript, err := txscript.PayToAddrScript(addr)
 		if err != nil {
-			log.Fatalf("❌ Failed to create change script: %v", err)
+			log.Fatalf("❌ Failed to create pkScript: %v", err)
 		}
-		tx.AddTxOut(wire.NewTxOut(int64(change), changeScript))
+		utxos, err := backend.ListUnspent(addr.EncodeAddress())
+		if err != nil {
+			log.Fatalf("❌ Failed to list unspent: %v", err)
+		}
+		for _, utxo := range utxos {
+			inputs = append(inputs, litecoinInputFor(utxo, pkScript))
+		}
+	}
+	return inputs
+}
+
+// litecoinTxVSize estimates the virtual size of a transaction spending
+// inputs to outputs with the given scripts
+func litecoinTxVSize(inputs []litecoinInput, outputScripts ...[]byte) int64 {
+	vsize := int64(11)
+	for _, input := range inputs {
+		if input.Witness {
+			vsize += 68
+		} else {
+			vsize += 148
+		}
+	}
+	for _, script := range outputScripts {
+		vsize += int64(9 + len(script))
 	}
+	return vsize
+}
+
+// signLitecoinTx signs every input of tx, whose inputs match inputs in order
+func signLitecoinTx(tx *wire.MsgTx, wif *btcutil.WIF, inputs []litecoinInput) {
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for _, input := range inputs {
+		prevOuts.AddPrevOut(*input.OutPoint, wire.NewTxOut(input.Value, input.PkScript))
+	}
+	sigHashes := txscript.NewTxSigHashes(tx, prevOuts)
 
-	// Sign transaction
 	for i, txIn := range tx.TxIn {
-		scriptPubKey, err := hex.DecodeString(utxos[i].ScriptPubKey)
-		if err != nil {
-			log.Fatalf("❌ Failed to decode scriptPubKey: %v", err)
+		input := inputs[i]
+		if input.Witness {
+			witness, err := txscript.WitnessSignature(tx, sigHashes, i, input.Value, input.PkScript, txscript.SigHashAll, wif.PrivKey, true)
+			if err != nil {
+				log.Fatalf("❌ Failed to sign: %v", err)
+			}
+			txIn.Witness = witness
+			continue
 		}
-		sigScript, err := txscript.SignatureScript(tx, i, scriptPubKey, txscript.SigHashAll, wif.PrivKey, true)
+		sigScript, err := txscript.SignatureScript(tx, i, input.PkScript, txscript.SigHashAll, wif.PrivKey, true)
 		if err != nil {
 			log.Fatalf("❌ Failed to sign: %v", err)
 		}
 		txIn.SignatureScript = sigScript
 	}
-
-	// Send transaction
-	txHash, err := client.SendRawTransaction(tx, false)
-	if err != nil {
-		log.Fatalf("❌ Failed to send transaction: %v", err)
-	}
-
-	fmt.Printf("✅ Transaction sent successfully!\n🔗 TxID: %s\n", txHash.String())
 }
 
 // SatoshisToLTC converts Satoshis to LTC
@@ -175,42 +250,45 @@ func SatoshisToLTC(satoshis int64) *big.Float {
 
 // LTCToSatoshis converts LTC to Satoshis
 func LTCToSatoshis(ltc float64) int64 {
-	return int64(ltc * 1e8)
+	return int64(math.Round(ltc * 1e8))
 }
 
 func main() {
 	// Litecoin network configurations
-	litecoinNetworks := map[string]map[string]string{
-		"Litecoin Mainnet": {
-			"rpc":  "https://api.blockcypher.com/v1/ltc/main", // Public API, no auth needed
-			"user": "",
-			"pass": "",
-		},
-		"Litecoin Testnet": {
-			"rpc":  "https://api.blockcypher.com/v1/ltc/test3", // Public API, no auth needed
-			"user": "",
-			"pass": "",
-		},
-	}
-
-	// Define Litecoin network parameters
-	netParams := &chaincfg.Params{
-		Name:             "litecoin",
-		PubKeyHashAddrID: 0x30, // Litecoin mainnet P2PKH address prefix (L)
-		ScriptHashAddrID: 0x32, // Litecoin mainnet P2SH address prefix (M)
-		PrivateKeyID:     0xB0, // Litecoin private key prefix
+	litecoinNetworks := map[string]struct {
+		Params  *chaincfg.Params
+		Backend LitecoinBackend
+	}{
+		"Litecoin Mainnet": {&LitecoinMainNetParams, ConnectLitecoinClient("https://api.blockcypher.com/v1/ltc/main", "")}, // Token optional, raises rate limits
+		"Litecoin Testnet": {&LitecoinTestNetParams, NewEsploraLitecoinBackend("https://litecoinspace.org/testnet/api")},
 	}
 
 	// Create a new Litecoin account
-	wif, address := CreateLitecoinAccount(netParams)
+	wif, address := CreateLitecoinAccount(&LitecoinMainNetParams)
 	fmt.Println("\n🏦 Litecoin Wallet Address:", address)
 	fmt.Println("\n🏦 Litecoin WIF:", wif.String())
 
+	// Create a new HD account (BIP84 ltc1 address, use LitecoinBIP44 for L... addresses)
+	_, _, hdAddress := CreateLitecoinHDAccount(LitecoinBIP84, &LitecoinMainNetParams)
+
 	// Check balances on Litecoin networks
 	fmt.Println("\n💰 Litecoin Balances:")
-	for name, config := range litecoinNetworks {
-		client := ConnectLitecoinClient(config["rpc"], config["user"], config["pass"])
-		balance := GetLitecoinBalance(client, address)
-		fmt.Printf("%s: %f LTC\n", name, balance)
+	for name, network := range litecoinNetworks {
+		_, address, err := LoadLitecoinAccount(wif.String(), network.Params)
+		if err != nil {
+			log.Fatal(err)
+		}
+		confirmed, unconfirmed := GetLitecoinBalance(network.Backend, address)
+		fmt.Printf("%s: %f LTC (unconfirmed: %f LTC)\n", name, confirmed, unconfirmed)
 	}
-}
\ No newline at end of file
+	confirmed, _ := GetLitecoinBalance(litecoinNetworks["Litecoin Mainnet"].Backend, hdAddress)
+	fmt.Printf("HD account %s: %f LTC\n", hdAddress, confirmed)
+
+	// Example: Send 0.001 LTC (uncomment to test)
+	// backend := litecoinNetworks["Litecoin Mainnet"].Backend
+	// SendLitecoinTransaction(backend, wif, "ltc1...", 0.001, &LitecoinMainNetParams)
+
+	// Example: Consolidate UTXOs below 0.001 LTC while fees are low, or sweep everything (uncomment to test)
+	// ConsolidateLitecoinUTXOs(backend, wif.String(), 0.001, 5, &LitecoinMainNetParams)
+	// SweepLitecoinAccount(backend, wif.String(), "L...", &LitecoinMainNetParams)
+}
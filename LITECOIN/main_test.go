@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// fakeLitecoinBackend serves fixed UTXOs per address and records the
+// broadcast transaction.
+type fakeLitecoinBackend struct {
+	utxos     map[string][]LitecoinUTXO
+	feeRate   int64
+	broadcast *wire.MsgTx
+}
+
+func (f *fakeLitecoinBackend) GetBalance(address string) (LitecoinBalance, error) {
+	return LitecoinBalance{}, nil
+}
+
+func (f *fakeLitecoinBackend) ListUnspent(address string) ([]LitecoinUTXO, error) {
+	return f.utxos[address], nil
+}
+
+func (f *fakeLitecoinBackend) EstimateFeeRate() (int64, error) {
+	return f.feeRate, nil
+}
+
+func (f *fakeLitecoinBackend) Broadcast(tx *wire.MsgTx) (string, error) {
+	// Keep the wire encoding so the test decodes what would be sent
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return "", err
+	}
+	f.broadcast = new(wire.MsgTx)
+	if err := f.broadcast.Deserialize(&buf); err != nil {
+		return "", err
+	}
+	return tx.TxHash().String(), nil
+}
+
+func testLitecoinKey(t *testing.T) (*btcutil.WIF, *btcutil.AddressPubKeyHash, *btcutil.AddressWitnessPubKeyHash) {
+	t.Helper()
+	privKey, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+	wif, err := btcutil.NewWIF(privKey, &LitecoinMainNetParams, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, segwit := litecoinKeyAddresses(wif, &LitecoinMainNetParams)
+	return wif, legacy, segwit
+}
+
+func testUTXO(n int, value int64, confirmations int64) LitecoinUTXO {
+	return LitecoinUTXO{TxID: fmt.Sprintf("%064x", n), Vout: uint32(n % 3), Value: value, Confirmations: confirmations}
+}
+
+// verifyLitecoinTx runs the script engine over every input of tx.
+func verifyLitecoinTx(t *testing.T, tx *wire.MsgTx, prevOuts map[wire.OutPoint]*wire.TxOut) {
+	t.Helper()
+	fetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+	for i, txIn := range tx.TxIn {
+		prevOut := prevOuts[txIn.PreviousOutPoint]
+		if prevOut == nil {
+			t.Fatalf("input %d spends unknown outpoint %s", i, txIn.PreviousOutPoint)
+		}
+		engine, err := txscript.NewEngine(prevOut.PkScript, tx, i, txscript.StandardVerifyFlags, nil, sigHashes, prevOut.Value, fetcher)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.Execute(); err != nil {
+			t.Errorf("input %d does not verify: %v", i, err)
+		}
+	}
+}
+
+// prevOutsFor maps the fake backend's UTXOs to their outputs.
+func prevOutsFor(t *testing.T, backend *fakeLitecoinBackend) map[wire.OutPoint]*wire.TxOut {
+	t.Helper()
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for address, utxos := range backend.utxos {
+		addr, err := btcutil.DecodeAddress(address, &LitecoinMainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkScript, _ := txscript.PayToAddrScript(addr)
+		for _, utxo := range utxos {
+			input := litecoinInputFor(utxo, pkScript)
+			prevOuts[*input.OutPoint] = wire.NewTxOut(utxo.Value, pkScript)
+		}
+	}
+	return prevOuts
+}
+
+func TestSendLitecoinTransactionRoundTrip(t *testing.T) {
+	wif, legacy, segwit := testLitecoinKey(t)
+	backend := &fakeLitecoinBackend{
+		feeRate: 10,
+		utxos: map[string][]LitecoinUTXO{
+			legacy.EncodeAddress(): {testUTXO(1, 40_000, 3)},
+			segwit.EncodeAddress(): {testUTXO(2, 100_000, 1)},
+		},
+	}
+	recipient, err := btcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{0x42}, 20), &LitecoinMainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipientScript, _ := txscript.PayToAddrScript(recipient)
+
+	SendLitecoinTransaction(backend, wif, recipient.EncodeAddress(), 0.0012, &LitecoinMainNetParams)
+	tx := backend.broadcast
+	if tx == nil {
+		t.Fatal("nothing was broadcast")
+	}
+
+	// Largest first: the P2WPKH output alone does not cover 120000, so
+	// both inputs are spent, the segwit one with a witness only
+	if len(tx.TxIn) != 2 {
+		t.Fatalf("tx has %d inputs, want 2", len(tx.TxIn))
+	}
+	if len(tx.TxIn[0].Witness) != 2 || len(tx.TxIn[0].SignatureScript) != 0 {
+		t.Errorf("P2WPKH input: witness %d items, sigScript %d bytes, want 2 and 0", len(tx.TxIn[0].Witness), len(tx.TxIn[0].SignatureScript))
+	}
+	if len(tx.TxIn[1].Witness) != 0 || len(tx.TxIn[1].SignatureScript) == 0 {
+		t.Errorf("P2PKH input: witness %d items, sigScript %d bytes, want a sigScript only", len(tx.TxIn[1].Witness), len(tx.TxIn[1].SignatureScript))
+	}
+
+	if len(tx.TxOut) != 2 {
+		t.Fatalf("tx has %d outputs, want payment and change", len(tx.TxOut))
+	}
+	if tx.TxOut[0].Value != 120_000 || !bytes.Equal(tx.TxOut[0].PkScript, recipientScript) {
+		t.Errorf("payment output = %d to %x, want 120000 to the recipient", tx.TxOut[0].Value, tx.TxOut[0].PkScript)
+	}
+	changeScript, _ := txscript.PayToAddrScript(legacy)
+	if !bytes.Equal(tx.TxOut[1].PkScript, changeScript) {
+		t.Errorf("change goes to %x, want the P2PKH address", tx.TxOut[1].PkScript)
+	}
+	fee := 140_000 - tx.TxOut[0].Value - tx.TxOut[1].Value
+	inputs := []litecoinInput{{Witness: true}, {}}
+	if want := litecoinTxVSize(inputs, recipientScript, changeScript) * backend.feeRate; fee != want {
+		t.Errorf("fee = %d, want %d", fee, want)
+	}
+
+	verifyLitecoinTx(t, tx, prevOutsFor(t, backend))
+}
+
+func TestConsolidateLitecoinUTXOsBothAddressTypes(t *testing.T) {
+	wif, legacy, segwit := testLitecoinKey(t)
+	backend := &fakeLitecoinBackend{
+		feeRate: 2,
+		utxos: map[string][]LitecoinUTXO{
+			legacy.EncodeAddress(): {testUTXO(1, 20_000, 5), testUTXO(2, 5_000_000, 5)},
+			segwit.EncodeAddress(): {testUTXO(3, 30_000, 2), testUTXO(4, 10_000, 0)},
+		},
+	}
+
+	ConsolidateLitecoinUTXOs(backend, wif.String(), 0.001, 5, &LitecoinMainNetParams)
+	tx := backend.broadcast
+	if tx == nil {
+		t.Fatal("nothing was broadcast")
+	}
+
+	// The large and the unconfirmed outputs stay put
+	if len(tx.TxIn) != 2 {
+		t.Fatalf("tx has %d inputs, want the two small confirmed ones", len(tx.TxIn))
+	}
+	changeScript, _ := txscript.PayToAddrScript(legacy)
+	if len(tx.TxOut) != 1 || !bytes.Equal(tx.TxOut[0].PkScript, changeScript) {
+		t.Fatalf("outputs = %v, want one output to the P2PKH address", tx.TxOut)
+	}
+	verifyLitecoinTx(t, tx, prevOutsFor(t, backend))
+
+	backend.broadcast = nil
+	ConsolidateLitecoinUTXOs(backend, wif.String(), 0.001, 1, &LitecoinMainNetParams)
+	if backend.broadcast != nil {
+		t.Error("consolidated above the maximum fee rate")
+	}
+}
+
+func TestSweepLitecoinAccountRoundTrip(t *testing.T) {
+	wif, legacy, segwit := testLitecoinKey(t)
+	backend := &fakeLitecoinBackend{
+		feeRate: 10,
+		utxos: map[string][]LitecoinUTXO{
+			legacy.EncodeAddress(): {testUTXO(1, 50_000, 1)},
+			segwit.EncodeAddress(): {testUTXO(2, 60_000, 1), testUTXO(3, 70_000, 0)},
+		},
+	}
+
+	SweepLitecoinAccount(backend, wif.String(), segwit.EncodeAddress(), &LitecoinMainNetParams)
+	tx := backend.broadcast
+	if tx == nil {
+		t.Fatal("nothing was broadcast")
+	}
+	if len(tx.TxIn) != 3 || len(tx.TxOut) != 1 {
+		t.Fatalf("tx has %d inputs and %d outputs, want 3 and 1", len(tx.TxIn), len(tx.TxOut))
+	}
+	verifyLitecoinTx(t, tx, prevOutsFor(t, backend))
+}
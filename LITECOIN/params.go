@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// LitecoinMainNetParams defines the network parameters for the Litecoin
+// main network
+var LitecoinMainNetParams = chaincfg.Params{
+	Name:        "litecoin",
+	Net:         wire.BitcoinNet(0xdbb6c0fb),
+	DefaultPort: "9333",
+
+	Bech32HRPSegwit: "ltc", // ltc1...
+
+	PubKeyHashAddrID: 0x30, // L...
+	ScriptHashAddrID: 0x32, // M...
+	PrivateKeyID:     0xB0, // 6... (uncompressed) or T... (compressed)
+
+	HDPrivateKeyID: [4]byte{0x01, 0x9d, 0x9c, 0xfe}, // Ltpv
+	HDPublicKeyID:  [4]byte{0x01, 0x9d, 0xa4, 0x62}, // Ltub
+	HDCoinType:     2,
+}
+
+// LitecoinTestNetParams defines the network parameters for the Litecoin
+// test network (testnet4)
+var LitecoinTestNetParams = chaincfg.Params{
+	Name:        "litecoin-testnet4",
+	Net:         wire.BitcoinNet(0xf1c8d2fd),
+	DefaultPort: "19335",
+
+	Bech32HRPSegwit: "tltc", // tltc1...
+
+	PubKeyHashAddrID: 0x6f, // m or n
+	ScriptHashAddrID: 0x3a, // Q...
+	PrivateKeyID:     0xef, // c...
+
+	HDPrivateKeyID: [4]byte{0x04, 0x36, 0xef, 0x7d}, // ttpv
+	HDPublicKeyID:  [4]byte{0x04, 0x36, 0xf6, 0xe1}, // ttub
+	HDCoinType:     1,
+}
+
+func init() {
+	// Registering makes btcutil recognise the address prefixes and lets
+	// hdkeychain convert Ltpv/ttpv keys to their public counterparts
+	for _, params := range []*chaincfg.Params{&LitecoinMainNetParams, &LitecoinTestNetParams} {
+		if err := chaincfg.Register(params); err != nil {
+			log.Fatalf("❌ Failed to register %s parameters: %v", params.Name, err)
+		}
+	}
+}
+
+// HD derivation purposes supported for Litecoin accounts
+const (
+	LitecoinBIP44 = 44 // P2PKH (L...)
+	LitecoinBIP84 = 84 // P2WPKH (ltc1...)
+)
+
+// CreateLitecoinHDAccount generates a new BIP39 mnemonic and derives the
+// first receive address of account 0 for the given purpose
+func CreateLitecoinHDAccount(purpose uint32, net *chaincfg.Params) (mnemonic string, wif *btcutil.WIF, address string) {
+	entropy, err := bip39.NewEntropy(128)
+	if err != nil {
+		log.Fatalf("❌ Failed to generate entropy: %v", err)
+	}
+	mnemonic, err = bip39.NewMnemonic(entropy)
+	if err != nil {
+		log.Fatalf("❌ Failed to generate mnemonic: %v", err)
+	}
+
+	wif, address, err = LoadLitecoinHDAccount(mnemonic, "", purpose, 0, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("✅ New Litecoin HD account created:")
+	fmt.Println("📝 Mnemonic:", mnemonic)
+	fmt.Printf("🛤️ Path: m/%d'/%d'/0'/0/0\n", purpose, net.HDCoinType)
+	fmt.Println("🔑 WIF:", wif.String())
+	fmt.Println("🏦 Address:", address)
+
+	return mnemonic, wif, address
+}
+
+// LoadLitecoinHDAccount derives the key and address at
+// m/purpose'/coin'/0'/0/index from a BIP39 mnemonic, as Litecoin wallets do
+func LoadLitecoinHDAccount(mnemonic, passphrase string, purpose, index uint32, net *chaincfg.Params) (*btcutil.WIF, string, error) {
+	if purpose != LitecoinBIP44 && purpose != LitecoinBIP84 {
+		return nil, "", fmt.Errorf("❌ Unsupported derivation purpose %d", purpose)
+	}
+
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Invalid mnemonic: %v", err)
+	}
+	key, err := hdkeychain.NewMaster(seed, net)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to create master key: %v", err)
+	}
+
+	path := []uint32{
+		hdkeychain.HardenedKeyStart + purpose,
+		hdkeychain.HardenedKeyStart + net.HDCoinType,
+		hdkeychain.HardenedKeyStart,
+		0,
+		index,
+	}
+	for _, step := range path {
+		key, err = key.Derive(step)
+		if err != nil {
+			return nil, "", fmt.Errorf("❌ Failed to derive key: %v", err)
+		}
+	}
+
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to get private key: %v", err)
+	}
+	wif, err := btcutil.NewWIF(privKey, net, true)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to create WIF: %v", err)
+	}
+
+	legacy, segwit := litecoinKeyAddresses(wif, net)
+	if purpose == LitecoinBIP84 {
+		return wif, segwit.EncodeAddress(), nil
+	}
+	return wif, legacy.EncodeAddress(), nil
+}
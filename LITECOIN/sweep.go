@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// DefaultLitecoinFeeRate is the fallback fee rate in litoshi/vB, matching
+// Litecoin Core's minimum relay fee of 0.0001 LTC/kB.
+const DefaultLitecoinFeeRate = 10
+
+// LitecoinDustLimit is the smallest P2PKH output relayed by default.
+const LitecoinDustLimit = 546
+
+// ConsolidateLitecoinUTXOs merges the confirmed UTXOs smaller than
+// thresholdLTC on both the P2PKH and P2WPKH addresses of the account into
+// one output to its P2PKH address, but only while the estimated fee rate
+// is at most maxFeeRate (litoshi/vB).
+func ConsolidateLitecoinUTXOs(backend LitecoinBackend, wifStr string, thresholdLTC float64, maxFeeRate int64, net *chaincfg.Params) {
+	wif, _, err := LoadLitecoinAccount(wifStr, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	feeRate, err := backend.EstimateFeeRate()
+	if err != nil {
+		log.Printf("❌ Fee estimation failed, not consolidating: %v", err)
+		return
+	}
+	if feeRate > maxFeeRate {
+		fmt.Printf("⏳ Fee rate %d litoshi/vB is above %d litoshi/vB, consolidation postponed\n", feeRate, maxFeeRate)
+		return
+	}
+
+	threshold := LTCToSatoshis(thresholdLTC)
+	var small []litecoinInput
+	for _, input := range listLitecoinInputs(backend, wif, net) {
+		if input.Confirmations > 0 && input.Value < threshold {
+			small = append(small, input)
+		}
+	}
+	if len(small) < 2 {
+		fmt.Printf("✅ Nothing to consolidate: %d UTXO(s) below %f LTC\n", len(small), thresholdLTC)
+		return
+	}
+
+	fromAddr, _ := litecoinKeyAddresses(wif, net)
+	pkScript, err := txscript.PayToAddrScript(fromAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to create pkScript: %v", err)
+	}
+
+	txHash := spendLitecoinUTXOs(backend, wif, small, pkScript, feeRate)
+	fmt.Printf("✅ Consolidated %d UTXOs\n🔗 TxID: %s\n", len(small), txHash)
+}
+
+// SweepLitecoinAccount sends the full spendable balance of a WIF, from both
+// its P2PKH and P2WPKH addresses, to toAddress with the fee deducted from
+// the amount.
+func SweepLitecoinAccount(backend LitecoinBackend, wifStr, toAddress string, net *chaincfg.Params) {
+	wif, _, err := LoadLitecoinAccount(wifStr, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	inputs := listLitecoinInputs(backend, wif, net)
+	if len(inputs) == 0 {
+		log.Fatal("❌ No unspent outputs available")
+	}
+
+	toAddr, err := btcutil.DecodeAddress(toAddress, net)
+	if err != nil {
+		log.Fatalf("❌ Invalid to address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(toAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to create pkScript: %v", err)
+	}
+
+	feeRate, err := backend.EstimateFeeRate()
+	if err != nil {
+		log.Printf("⚠️ Fee estimation unavailable (%v), using %d litoshi/vB", err, DefaultLitecoinFeeRate)
+		feeRate = DefaultLitecoinFeeRate
+	}
+
+	txHash := spendLitecoinUTXOs(backend, wif, inputs, pkScript, feeRate)
+	fmt.Printf("✅ Swept %d UTXOs to %s\n🔗 TxID: %s\n", len(inputs), toAddress, txHash)
+}
+
+// litecoinInputFor converts an indexer UTXO paying to pkScript
+func litecoinInputFor(utxo LitecoinUTXO, pkScript []byte) litecoinInput {
+	// Indexers report txids in display (reversed) byte order
+	hash, err := chainhash.NewHashFromStr(utxo.TxID)
+	if err != nil {
+		log.Fatalf("❌ Invalid txid: %v", err)
+	}
+	return litecoinInput{
+		OutPoint:      wire.NewOutPoint(hash, utxo.Vout),
+		Value:         utxo.Value,
+		PkScript:      pkScript,
+		Witness:       txscript.IsPayToWitnessPubKeyHash(pkScript),
+		Confirmations: utxo.Confirmations,
+	}
+}
+
+// spendLitecoinUTXOs spends inputs to a single output carrying the total
+// minus the fee and broadcasts the transaction.
+func spendLitecoinUTXOs(backend LitecoinBackend, wif *btcutil.WIF, inputs []litecoinInput, pkScript []byte, feeRate int64) string {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	var total int64
+	for _, input := range inputs {
+		tx.AddTxIn(wire.NewTxIn(input.OutPoint, nil, nil))
+		total += input.Value
+	}
+
+	fee := litecoinTxVSize(inputs, pkScript) * feeRate
+	if total-fee < LitecoinDustLimit {
+		log.Fatalf("❌ Balance of %d litoshi does not cover the %d litoshi fee", total, fee)
+	}
+	tx.AddTxOut(wire.NewTxOut(total-fee, pkScript))
+
+	signLitecoinTx(tx, wif, inputs)
+
+	txHash, err := backend.Broadcast(tx)
+	if err != nil {
+		log.Fatalf("❌ Failed to send transaction: %v", err)
+	}
+	return txHash
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// TronBackend is the subset of node operations needed to check balances
+// and send TRX. *client.GrpcClient implements it over gRPC and
+// *TronGridClient over TronGrid's HTTP API.
+type TronBackend interface {
+	GetAccount(addr string) (*core.Account, error)
+	Transfer(from, toAddress string, amount int64) (*api.TransactionExtention, error)
+	Broadcast(tx *core.Transaction) (*api.Return, error)
+}
+
+// TronGridClient talks to the /wallet/* HTTP endpoints of TronGrid or any
+// java-tron full node.
+type TronGridClient struct {
+	BaseURL    string
+	APIKey     string // sent as TRON-PRO-API-KEY when set
+	HTTPClient *http.Client
+}
+
+// -------------------------------
+// 🔗 Connect to TronGrid
+// -------------------------------
+
+// ConnectTronGrid creates a TronGrid HTTP client, e.g. for
+// https://api.trongrid.io.
+func ConnectTronGrid(baseURL, apiKey string) *TronGridClient {
+	return &TronGridClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetAccount returns the account of a base58 address, or an "account not
+// found" error like the gRPC client for accounts never activated.
+func (t *TronGridClient) GetAccount(addr string) (*core.Account, error) {
+	var resp struct {
+		Address string `json:"address"`
+		Balance int64  `json:"balance"`
+	}
+	err := t.post("/wallet/getaccount", map[string]interface{}{"address": addr, "visible": true}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Address == "" {
+		return nil, errors.New("account not found")
+	}
+	owner, err := address.Base58ToAddress(resp.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Account{Address: owner.Bytes(), Balance: resp.Balance}, nil
+}
+
+// Transfer builds an unsigned TRX transfer of amount sun.
+func (t *TronGridClient) Transfer(from, toAddress string, amount int64) (*api.TransactionExtention, error) {
+	var resp tronGridTransaction
+	err := t.post("/wallet/createtransaction", map[string]interface{}{
+		"owner_address": from,
+		"to_address":    toAddress,
+		"amount":        amount,
+		"visible":       true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := address.Base58ToAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := address.Base58ToAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	return resp.transactionExtention(&core.TransferContract{OwnerAddress: owner.Bytes(), ToAddress: to.Bytes(), Amount: amount})
+}
+
+// Broadcast publishes a signed transaction via /wallet/broadcasthex.
+func (t *TronGridClient) Broadcast(tx *core.Transaction) (*api.Return, error) {
+	raw, err := proto.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result  bool   `json:"result"`
+		Code    string `json:"code"`
+		Message string `json:"message"` // hex-encoded
+	}
+	if err := t.post("/wallet/broadcasthex", map[string]string{"transaction": hex.EncodeToString(raw)}, &resp); err != nil {
+		return nil, err
+	}
+
+	message, err := hex.DecodeString(resp.Message)
+	if err != nil {
+		message = []byte(resp.Message)
+	}
+	return &api.Return{
+		Result:  resp.Result,
+		Code:    api.ReturnResponseCode(api.ReturnResponseCode_value[resp.Code]),
+		Message: message,
+	}, nil
+}
+
+// tronGridTransaction is an unsigned transaction as returned by the
+// /wallet/create* endpoints.
+type tronGridTransaction struct {
+	TxID       string `json:"txID"`
+	RawDataHex string `json:"raw_data_hex"`
+	Error      string `json:"Error"`
+}
+
+// transactionExtention decodes the raw transaction and checks that txID
+// matches it and that its only contract is the requested transfer, so a
+// tampered response cannot get another tx or recipient signed.
+func (tx tronGridTransaction) transactionExtention(want *core.TransferContract) (*api.TransactionExtention, error) {
+	if tx.Error != "" {
+		return nil, errors.New(tx.Error)
+	}
+	rawBytes, err := hex.DecodeString(tx.RawDataHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw_data_hex: %w", err)
+	}
+	hash := sha256.Sum256(rawBytes)
+	if hex.EncodeToString(hash[:]) != tx.TxID {
+		return nil, fmt.Errorf("txID %s does not match raw_data_hex", tx.TxID)
+	}
+
+	raw := new(core.TransactionRaw)
+	if err := proto.Unmarshal(rawBytes, raw); err != nil {
+		return nil, fmt.Errorf("invalid raw_data_hex: %w", err)
+	}
+	if err := checkTransferContract(raw, want); err != nil {
+		return nil, err
+	}
+	return &api.TransactionExtention{
+		Transaction: &core.Transaction{RawData: raw},
+		Txid:        hash[:],
+		Result:      &api.Return{Result: true},
+	}, nil
+}
+
+// checkTransferContract rejects raw transactions that are anything but a
+// single TransferContract with the given owner, recipient and amount.
+func checkTransferContract(raw *core.TransactionRaw, want *core.TransferContract) error {
+	if len(raw.Contract) != 1 || raw.Contract[0].Type != core.Transaction_Contract_TransferContract {
+		return fmt.Errorf("transaction is not a single TransferContract")
+	}
+	got := new(core.TransferContract)
+	if err := raw.Contract[0].Parameter.UnmarshalTo(got); err != nil {
+		return fmt.Errorf("invalid TransferContract: %w", err)
+	}
+	if !bytes.Equal(got.OwnerAddress, want.OwnerAddress) || !bytes.Equal(got.ToAddress, want.ToAddress) || got.Amount != want.Amount {
+		return fmt.Errorf("transfer of %d sun from %s to %s does not match the requested %d sun from %s to %s",
+			got.Amount, address.Address(got.OwnerAddress), address.Address(got.ToAddress),
+			want.Amount, address.Address(want.OwnerAddress), address.Address(want.ToAddress))
+	}
+	return nil
+}
+
+func (t *TronGridClient) post(path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, t.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return t.do(req, path, out)
+}
+
+func (t *TronGridClient) do(req *http.Request, path string, out interface{}) error {
+	if t.APIKey != "" {
+		req.Header.Set("TRON-PRO-API-KEY", t.APIKey)
+	}
+
+	resp, err := t.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, out)
+}
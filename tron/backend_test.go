@@ -0,0 +1,237 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	testOwner    = "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8"
+	testReceiver = "TVjsyZ7fYF3qLF6BQgPmTEZy1xrNNyVAAA"
+)
+
+// newTronGridStub serves handler on an httptest server and rejects requests
+// that do not carry the API key.
+func newTronGridStub(t *testing.T, handler http.HandlerFunc) *TronGridClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("TRON-PRO-API-KEY"); key != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"Error": "ApiKey %q is invalid"}`, key)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return ConnectTronGrid(server.URL+"/", "secret")
+}
+
+func decodeBody(t *testing.T, r *http.Request) map[string]interface{} {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("invalid request body: %v", err)
+	}
+	return body
+}
+
+// testRawTransaction encodes an unsigned transaction and its txID.
+func testRawTransaction(t *testing.T, timestamp int64, contracts ...*core.Transaction_Contract) (string, string) {
+	raw, err := proto.Marshal(&core.TransactionRaw{
+		RefBlockBytes: []byte{0x12, 0x34},
+		RefBlockHash:  []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		Expiration:    timestamp + 60000,
+		Timestamp:     timestamp,
+		Contract:      contracts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(raw)
+	return hex.EncodeToString(raw), hex.EncodeToString(hash[:])
+}
+
+// testTransferContract is the contract of a TRX transfer of amount sun.
+func testTransferContract(t *testing.T, from, to string, amount int64) *core.Transaction_Contract {
+	owner, err := address.Base58ToAddress(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver, err := address.Base58ToAddress(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parameter, err := anypb.New(&core.TransferContract{OwnerAddress: owner.Bytes(), ToAddress: receiver.Bytes(), Amount: amount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &core.Transaction_Contract{Type: core.Transaction_Contract_TransferContract, Parameter: parameter}
+}
+
+func TestTronGridAPIKey(t *testing.T) {
+	var paths []string
+	client := newTronGridStub(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprintf(w, `{"address": %q, "balance": 1000000}`, testOwner)
+	})
+
+	if _, err := client.GetAccount(testOwner); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "/wallet/getaccount" {
+		t.Errorf("requested %v, want /wallet/getaccount without a double slash", paths)
+	}
+
+	client.APIKey = "wrong"
+	if _, err := client.GetAccount(testOwner); err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("err = %v, want the HTTP 401 of a bad API key", err)
+	}
+
+	// Without a key the header must not be sent at all.
+	var header []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Values("TRON-PRO-API-KEY")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+	ConnectTronGrid(server.URL, "").GetAccount(testOwner)
+	if len(header) != 0 {
+		t.Errorf("sent TRON-PRO-API-KEY %v without a key", header)
+	}
+}
+
+func TestTronGridGetAccount(t *testing.T) {
+	client := newTronGridStub(t, func(w http.ResponseWriter, r *http.Request) {
+		body := decodeBody(t, r)
+		if body["visible"] != true {
+			t.Errorf("request %v is not in base58 (visible) form", body)
+		}
+		if body["address"] == testOwner {
+			fmt.Fprintf(w, `{"address": %q, "balance": 2500000}`, testOwner)
+			return
+		}
+		// java-tron answers an empty object for accounts never activated
+		fmt.Fprint(w, `{}`)
+	})
+
+	account, err := client.GetAccount(testOwner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != 2500000 {
+		t.Errorf("balance = %d, want 2500000", account.Balance)
+	}
+
+	if _, err := client.GetAccount(testReceiver); err == nil || err.Error() != "account not found" {
+		t.Errorf("err = %v, want the gRPC client's account not found error", err)
+	}
+}
+
+func TestTronGridTransfer(t *testing.T) {
+	transfer := testTransferContract(t, testOwner, testReceiver, 1000)
+	rawHex, txID := testRawTransaction(t, 1700000000000, transfer)
+	otherRawHex, _ := testRawTransaction(t, 1700000000001, transfer)
+	swappedToHex, swappedToID := testRawTransaction(t, 1700000000000, testTransferContract(t, testOwner, testOwner, 1000))
+	swappedAmountHex, swappedAmountID := testRawTransaction(t, 1700000000000, testTransferContract(t, testOwner, testReceiver, 1000000))
+	noContractHex, noContractID := testRawTransaction(t, 1700000000000)
+
+	tests := []struct {
+		name     string
+		response string
+		err      string
+	}{
+		{"valid", fmt.Sprintf(`{"txID": %q, "raw_data_hex": %q}`, txID, rawHex), ""},
+		{"raw data swapped", fmt.Sprintf(`{"txID": %q, "raw_data_hex": %q}`, txID, otherRawHex), "does not match"},
+		{"txID swapped", fmt.Sprintf(`{"txID": %q, "raw_data_hex": %q}`, strings.Repeat("00", 32), rawHex), "does not match"},
+		{"to_address swapped", fmt.Sprintf(`{"txID": %q, "raw_data_hex": %q}`, swappedToID, swappedToHex), "does not match the requested"},
+		{"amount swapped", fmt.Sprintf(`{"txID": %q, "raw_data_hex": %q}`, swappedAmountID, swappedAmountHex), "does not match the requested"},
+		{"no contract", fmt.Sprintf(`{"txID": %q, "raw_data_hex": %q}`, noContractID, noContractHex), "not a single TransferContract"},
+		{"invalid hex", fmt.Sprintf(`{"txID": %q, "raw_data_hex": "zz"}`, txID), "invalid raw_data_hex"},
+		{"node error", `{"Error": "class org.tron.core.exception.ContractValidateException : balance is not sufficient."}`, "balance is not sufficient"},
+	}
+	for _, test := range tests {
+		client := newTronGridStub(t, func(w http.ResponseWriter, r *http.Request) {
+			body := decodeBody(t, r)
+			if r.URL.Path != "/wallet/createtransaction" || body["owner_address"] != testOwner ||
+				body["to_address"] != testReceiver || body["amount"] != float64(1000) {
+				t.Errorf("%s: unexpected request %s %v", test.name, r.URL.Path, body)
+			}
+			fmt.Fprint(w, test.response)
+		})
+
+		tx, err := client.Transfer(testOwner, testReceiver, 1000)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: err = %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if hex.EncodeToString(tx.Txid) != txID || tx.Transaction.RawData.Timestamp != 1700000000000 {
+			t.Errorf("%s: got txID %x at %d", test.name, tx.Txid, tx.Transaction.RawData.Timestamp)
+		}
+	}
+}
+
+func TestTronGridBroadcast(t *testing.T) {
+	rawHex, txID := testRawTransaction(t, 1700000000000)
+	rawBytes, _ := hex.DecodeString(rawHex)
+	raw := new(core.TransactionRaw)
+	if err := proto.Unmarshal(rawBytes, raw); err != nil {
+		t.Fatal(err)
+	}
+	signed := &core.Transaction{RawData: raw, Signature: [][]byte{make([]byte, 65)}}
+
+	tests := []struct {
+		name     string
+		response string
+		want     *api.Return
+	}{
+		{"accepted", fmt.Sprintf(`{"result": true, "txid": %q}`, txID), &api.Return{Result: true, Code: api.Return_SUCCESS}},
+		{
+			"signature error with hex message",
+			fmt.Sprintf(`{"code": "SIGERROR", "txid": %q, "message": %q}`, txID, hex.EncodeToString([]byte("validate signature error"))),
+			&api.Return{Code: api.Return_SIGERROR, Message: []byte("validate signature error")},
+		},
+		{
+			"plain text message",
+			`{"code": "TRANSACTION_EXPIRATION_ERROR", "message": "Transaction expired"}`,
+			&api.Return{Code: api.Return_TRANSACTION_EXPIRATION_ERROR, Message: []byte("Transaction expired")},
+		},
+		{"unknown code", `{"code": "SOMETHING_NEW"}`, &api.Return{Code: api.Return_SUCCESS}},
+	}
+	for _, test := range tests {
+		client := newTronGridStub(t, func(w http.ResponseWriter, r *http.Request) {
+			body := decodeBody(t, r)
+			sent, _ := hex.DecodeString(fmt.Sprint(body["transaction"]))
+			decoded := new(core.Transaction)
+			if r.URL.Path != "/wallet/broadcasthex" || proto.Unmarshal(sent, decoded) != nil || !proto.Equal(decoded, signed) {
+				t.Errorf("%s: broadcast %s %v is not the signed transaction", test.name, r.URL.Path, body)
+			}
+			fmt.Fprint(w, test.response)
+		})
+
+		result, err := client.Broadcast(signed)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if result.Result != test.want.Result || result.Code != test.want.Code || string(result.Message) != string(test.want.Message) {
+			t.Errorf("%s: got %v/%v/%q, want %v/%v/%q", test.name,
+				result.Result, result.Code, result.Message, test.want.Result, test.want.Code, test.want.Message)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	eabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fbsobreira/gotron-sdk/pkg/abi"
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/contract"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultOriginEnergyLimit caps the energy the deployer provides per call
+// when callers pay only part of it.
+const DefaultOriginEnergyLimit = 10_000_000
+
+// DeployOptions configures DeployContract.
+type DeployOptions struct {
+	FeeLimit int64 // sun, DefaultFeeLimit when 0
+	// UserResourcePercent is the share of energy paid by callers (0-100);
+	// the deployer pays the rest up to OriginEnergyLimit per call.
+	UserResourcePercent int64
+	OriginEnergyLimit   int64 // DefaultOriginEnergyLimit when 0
+	// ConstructorParams are the constructor arguments in the JSON form of
+	// TriggerContract, e.g. [{"uint256":"1000"},{"address":"T..."}].
+	ConstructorParams string
+}
+
+// -------------------------------
+// 📦 Deploy Contract
+// -------------------------------
+
+// DeployContract deploys bytecode with its ABI and returns the txid and
+// the address the contract will have once the transaction is confirmed.
+func DeployContract(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, name, abiJSON, bytecodeHex string, opts DeployOptions) (string, address.Address) {
+	if opts.FeeLimit == 0 {
+		opts.FeeLimit = DefaultFeeLimit
+	}
+	if opts.OriginEnergyLimit == 0 {
+		opts.OriginEnergyLimit = DefaultOriginEnergyLimit
+	}
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+
+	contractABI, err := contract.JSONtoABI(abiJSON)
+	if err != nil {
+		log.Fatalf("❌ Invalid contract ABI: %v", err)
+	}
+
+	// Constructor arguments are ABI-encoded after the bytecode
+	code := strings.TrimPrefix(bytecodeHex, "0x")
+	if opts.ConstructorParams != "" {
+		params, err := abi.LoadFromJSON(opts.ConstructorParams)
+		if err != nil {
+			log.Fatalf("❌ Invalid constructor params: %v", err)
+		}
+		encoded, err := abi.GetPaddedParam(params)
+		if err != nil {
+			log.Fatalf("❌ Failed to encode constructor params: %v", err)
+		}
+		code += hex.EncodeToString(encoded)
+	}
+
+	txExt, err := c.DeployContract(fromAddr.String(), name, contractABI, code,
+		opts.FeeLimit, opts.UserResourcePercent, opts.OriginEnergyLimit)
+	if err != nil {
+		log.Fatalf("❌ Failed to create deploy transaction: %v", err)
+	}
+
+	contractAddr := deployedContractAddress(txExt.Transaction, fromAddr)
+	fmt.Printf("📦 Deploying %s to %s (fee limit %f TRX)\n", name, contractAddr.String(), SunToTrx(opts.FeeLimit))
+	return signAndBroadcast(c, privateKey, txExt), contractAddr
+}
+
+// deployedContractAddress derives the address of a contract created by tx
+// the way java-tron does: keccak256(sha256(raw_data) || owner), last 20
+// bytes, with the 0x41 prefix.
+func deployedContractAddress(tx *core.Transaction, owner address.Address) address.Address {
+	raw, err := proto.Marshal(tx.RawData)
+	if err != nil {
+		log.Fatalf("❌ Failed to encode transaction: %v", err)
+	}
+	txHash := sha256.Sum256(raw)
+	hash := crypto.Keccak256(append(txHash[:], owner.Bytes()...))
+	return address.Address(append([]byte{address.TronBytePrefix}, hash[12:]...))
+}
+
+// -------------------------------
+// 📞 Call Contract Methods
+// -------------------------------
+
+// CallContract runs a constant (view) call and decodes the result with the
+// contract ABI, fetched from the chain when contractABI is nil. method is
+// the full signature, e.g. "balanceOf(address)", and params use the JSON
+// form of TriggerContract.
+func CallContract(c *client.GrpcClient, contractAddr, method, params string, contractABI *core.SmartContract_ABI) []interface{} {
+	result, err := c.TriggerConstantContract("", contractAddr, method, params)
+	if err != nil {
+		log.Fatalf("❌ Failed to call %s: %v", method, err)
+	}
+	output := constantResult(result)
+	if result.GetResult().GetCode() != 0 || isRevert(result) {
+		log.Fatalf("❌ Call to %s reverted: %s", method, revertReason(result, output))
+	}
+
+	if contractABI == nil {
+		if contractABI, err = c.GetContractABI(contractAddr); err != nil {
+			log.Fatalf("❌ Failed to get contract ABI: %v", err)
+		}
+	}
+	name, _, _ := strings.Cut(method, "(")
+	outputs, err := abi.GetParser(contractABI, name)
+	if err != nil {
+		log.Fatalf("❌ Method %s not found in ABI: %v", name, err)
+	}
+	values, err := outputs.Unpack(output)
+	if err != nil {
+		log.Fatalf("❌ Failed to decode %s result: %v", method, err)
+	}
+	return values
+}
+
+// TriggerContract sends a state-changing call after checking that its
+// energy burn fits in feeLimit (sun, DefaultFeeLimit when 0).
+func TriggerContract(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, contractAddr, method, params string, callValueTrx float64, feeLimit int64) string {
+	if feeLimit == 0 {
+		feeLimit = DefaultFeeLimit
+	}
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	callValue := TrxToSun(callValueTrx)
+
+	estimate := estimateContractEnergy(c, fromAddr, contractAddr, method, params, callValue)
+	fmt.Printf("⚡ Energy: %d required, %d available, burns up to %f TRX\n", estimate.Energy, estimate.Available, SunToTrx(estimate.BurnSun))
+	if estimate.BurnSun > feeLimit {
+		log.Fatalf("❌ Energy burn of %f TRX exceeds fee limit of %f TRX", SunToTrx(estimate.BurnSun), SunToTrx(feeLimit))
+	}
+
+	txExt, err := c.TriggerContract(fromAddr.String(), contractAddr, method, params, feeLimit, callValue, "", 0)
+	if err != nil {
+		log.Fatalf("❌ Failed to create transaction: %v", err)
+	}
+	fmt.Printf("📞 Calling %s on %s\n", method, contractAddr)
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// FormatContractValue renders a decoded ABI value, showing addresses in
+// Tron base58 form.
+func FormatContractValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return address.Address(append([]byte{address.TronBytePrefix}, v.Bytes()...)).String()
+	case []common.Address:
+		formatted := make([]string, len(v))
+		for i, a := range v {
+			formatted[i] = FormatContractValue(a)
+		}
+		return "[" + strings.Join(formatted, ", ") + "]"
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// constantResult returns the return data of a constant call.
+func constantResult(result *api.TransactionExtention) []byte {
+	if len(result.ConstantResult) == 0 {
+		return nil
+	}
+	return result.ConstantResult[0]
+}
+
+// isRevert reports whether a constant call's execution reverted.
+func isRevert(result *api.TransactionExtention) bool {
+	ret := result.GetTransaction().GetRet()
+	return len(ret) > 0 && ret[0].ContractRet != core.Transaction_Result_SUCCESS && ret[0].ContractRet != core.Transaction_Result_DEFAULT
+}
+
+// revertReason decodes a revert message from Error(string) return data,
+// falling back to the node's message.
+func revertReason(result *api.TransactionExtention, output []byte) string {
+	if reason, err := eabi.UnpackRevert(output); err == nil {
+		return reason
+	}
+	if message := bytes.TrimSpace(result.GetResult().GetMessage()); len(message) > 0 {
+		return string(message)
+	}
+	return "0x" + hex.EncodeToString(output)
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+)
+
+// depositPollInterval is how often WatchDeposits queries TronGrid.
+const depositPollInterval = 10 * time.Second
+
+// TronTxInfo is the execution result of a confirmed transaction.
+type TronTxInfo struct {
+	TxID           string
+	BlockNumber    int64
+	BlockTime      time.Time
+	Success        bool
+	Result         string // contract result, e.g. SUCCESS or REVERT
+	Message        string // revert or failure message
+	FeeSun         int64  // total TRX burned
+	EnergyUsed     int64
+	EnergyFeeSun   int64
+	BandwidthUsed  int64
+	BandwidthFee   int64
+	ContractResult []byte
+}
+
+// TronTransfer is a TRX or TRC-20 transfer from an address's history.
+// Token is empty for TRX.
+type TronTransfer struct {
+	TxID      string
+	From      string
+	To        string
+	Token     string
+	Symbol    string
+	Decimals  int
+	Amount    *big.Int
+	Timestamp time.Time
+	Success   bool
+}
+
+// -------------------------------
+// 🧾 Transaction Info
+// -------------------------------
+
+// GetTransactionInfo returns fee, resource usage and result of a
+// confirmed transaction, and false while it is not yet in a block.
+func GetTransactionInfo(c *client.GrpcClient, txID string) (TronTxInfo, bool) {
+	info, err := c.GetTransactionInfoByID(txID)
+	if err != nil {
+		if err.Error() == "transaction info not found" {
+			return TronTxInfo{}, false
+		}
+		log.Fatalf("❌ Failed to get transaction info: %v", err)
+	}
+
+	receipt := info.GetReceipt()
+	result := TronTxInfo{
+		TxID:          txID,
+		BlockNumber:   info.BlockNumber,
+		BlockTime:     time.UnixMilli(info.BlockTimeStamp),
+		Success:       info.Result == core.TransactionInfo_SUCESS,
+		Result:        receipt.GetResult().String(),
+		Message:       string(info.ResMessage),
+		FeeSun:        info.Fee,
+		EnergyUsed:    receipt.GetEnergyUsageTotal(),
+		EnergyFeeSun:  receipt.GetEnergyFee(),
+		BandwidthUsed: receipt.GetNetUsage(),
+		BandwidthFee:  receipt.GetNetFee(),
+	}
+	if len(info.ContractResult) > 0 {
+		result.ContractResult = info.ContractResult[0]
+	}
+	// Contract calls can fail while the transaction itself is accepted
+	if receipt.GetResult() != core.Transaction_Result_DEFAULT && receipt.GetResult() != core.Transaction_Result_SUCCESS {
+		result.Success = false
+	}
+	return result, true
+}
+
+// WaitForTransactionInfo polls until the transaction is in a block.
+func WaitForTransactionInfo(c *client.GrpcClient, txID string) TronTxInfo {
+	for {
+		if info, ok := GetTransactionInfo(c, txID); ok {
+			return info
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
+
+// PrintTransactionInfo prints the result of a transaction.
+func PrintTransactionInfo(info TronTxInfo) {
+	status := "✅ Success"
+	if !info.Success {
+		status = "❌ Failed"
+	}
+	fmt.Printf("%s: %s in block %d at %s\n", status, info.TxID, info.BlockNumber, info.BlockTime.Format(time.RFC3339))
+	if info.Result != "" && info.Result != core.Transaction_Result_DEFAULT.String() {
+		fmt.Printf("   Result: %s %s\n", info.Result, info.Message)
+	}
+	fmt.Printf("   Fee: %f TRX (energy %d, %f TRX; bandwidth %d, %f TRX)\n",
+		SunToTrx(info.FeeSun), info.EnergyUsed, SunToTrx(info.EnergyFeeSun), info.BandwidthUsed, SunToTrx(info.BandwidthFee))
+}
+
+// -------------------------------
+// 📜 Transfer History
+// -------------------------------
+
+// HistoryQuery filters ListTRXTransfers and ListTRC20Transfers.
+type HistoryQuery struct {
+	Limit        int       // page size, at most 200
+	Fingerprint  string    // cursor returned by the previous page
+	OnlyIncoming bool      // only transfers to the address
+	Since        time.Time // oldest block time to include
+	Contract     string    // TRC-20 contract to filter on
+}
+
+// ListTRXTransfers returns one page of confirmed TRX transfers of addr,
+// newest first, and the fingerprint of the next page ("" on the last).
+func (t *TronGridClient) ListTRXTransfers(addr string, query HistoryQuery) ([]TronTransfer, string, error) {
+	var resp struct {
+		Data []struct {
+			TxID           string `json:"txID"`
+			BlockTimestamp int64  `json:"block_timestamp"`
+			RawData        struct {
+				Contract []struct {
+					Type      string `json:"type"`
+					Parameter struct {
+						Value struct {
+							Amount       int64  `json:"amount"`
+							OwnerAddress string `json:"owner_address"`
+							ToAddress    string `json:"to_address"`
+						} `json:"value"`
+					} `json:"parameter"`
+				} `json:"contract"`
+			} `json:"raw_data"`
+			Ret []struct {
+				ContractRet string `json:"contractRet"`
+			} `json:"ret"`
+		} `json:"data"`
+		Meta tronGridMeta `json:"meta"`
+	}
+	if err := t.getV1("/v1/accounts/"+addr+"/transactions", query, &resp); err != nil {
+		return nil, "", err
+	}
+
+	var transfers []TronTransfer
+	for _, tx := range resp.Data {
+		for _, contract := range tx.RawData.Contract {
+			if contract.Type != "TransferContract" {
+				continue
+			}
+			value := contract.Parameter.Value
+			transfers = append(transfers, TronTransfer{
+				TxID:      tx.TxID,
+				From:      address.HexToAddress(value.OwnerAddress).String(),
+				To:        address.HexToAddress(value.ToAddress).String(),
+				Symbol:    "TRX",
+				Decimals:  6,
+				Amount:    big.NewInt(value.Amount),
+				Timestamp: time.UnixMilli(tx.BlockTimestamp),
+				Success:   len(tx.Ret) == 0 || tx.Ret[0].ContractRet == "SUCCESS",
+			})
+		}
+	}
+	return transfers, resp.Meta.Fingerprint, nil
+}
+
+// ListTRC20Transfers returns one page of confirmed TRC-20 transfers of
+// addr, newest first, and the fingerprint of the next page.
+func (t *TronGridClient) ListTRC20Transfers(addr string, query HistoryQuery) ([]TronTransfer, string, error) {
+	var resp struct {
+		Data []struct {
+			TransactionID  string `json:"transaction_id"`
+			BlockTimestamp int64  `json:"block_timestamp"`
+			From           string `json:"from"`
+			To             string `json:"to"`
+			Type           string `json:"type"`
+			Value          string `json:"value"`
+			TokenInfo      struct {
+				Address  string `json:"address"`
+				Symbol   string `json:"symbol"`
+				Decimals int    `json:"decimals"`
+			} `json:"token_info"`
+		} `json:"data"`
+		Meta tronGridMeta `json:"meta"`
+	}
+	if err := t.getV1("/v1/accounts/"+addr+"/transactions/trc20", query, &resp); err != nil {
+		return nil, "", err
+	}
+
+	var transfers []TronTransfer
+	for _, event := range resp.Data {
+		if event.Type != "Transfer" {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(event.Value, 10)
+		if !ok {
+			return nil, "", fmt.Errorf("invalid TRC-20 value %q in %s", event.Value, event.TransactionID)
+		}
+		transfers = append(transfers, TronTransfer{
+			TxID:      event.TransactionID,
+			From:      event.From,
+			To:        event.To,
+			Token:     event.TokenInfo.Address,
+			Symbol:    event.TokenInfo.Symbol,
+			Decimals:  event.TokenInfo.Decimals,
+			Amount:    amount,
+			Timestamp: time.UnixMilli(event.BlockTimestamp),
+			Success:   true, // TronGrid only indexes emitted Transfer events
+		})
+	}
+	return transfers, resp.Meta.Fingerprint, nil
+}
+
+// tronGridMeta is the pagination block of TronGrid v1 responses.
+type tronGridMeta struct {
+	Fingerprint string `json:"fingerprint"`
+}
+
+func (t *TronGridClient) getV1(path string, query HistoryQuery, out interface{}) error {
+	params := url.Values{"only_confirmed": {"true"}}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Fingerprint != "" {
+		params.Set("fingerprint", query.Fingerprint)
+	}
+	if query.OnlyIncoming {
+		params.Set("only_to", "true")
+	}
+	if !query.Since.IsZero() {
+		params.Set("min_timestamp", strconv.FormatInt(query.Since.UnixMilli(), 10))
+	}
+	if query.Contract != "" {
+		params.Set("contract_address", query.Contract)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, t.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	return t.do(req, path, out)
+}
+
+// PrintTransfer prints a transfer from the point of view of addr.
+func PrintTransfer(addr string, transfer TronTransfer) {
+	direction := "📤 Sent to " + transfer.To
+	if transfer.To == addr {
+		direction = "📥 Received from " + transfer.From
+	}
+	status := ""
+	if !transfer.Success {
+		status = " (failed)"
+	}
+	amount := BaseUnitsToToken(transfer.Amount, transfer.Decimals).Text('f', transfer.Decimals)
+	fmt.Printf("%s: %s %s at %s%s\n   tx %s\n", direction, amount, transfer.Symbol,
+		transfer.Timestamp.Format(time.RFC3339), status, transfer.TxID)
+}
+
+// -------------------------------
+// 📥 Watch Incoming Deposits
+// -------------------------------
+
+// depositOverlap is how far before a feed's newest transfer the next poll
+// starts, so transfers TronGrid indexes late are still picked up. seen
+// dedupes the transfers polled twice.
+const depositOverlap = 2 * time.Minute
+
+// WatchDeposits polls TronGrid for confirmed TRX and TRC-20 transfers to
+// the watched addresses that arrive after it starts, and sends each one
+// to deposits once. It runs until ctx is done.
+func WatchDeposits(ctx context.Context, t *TronGridClient, watched []string, deposits chan<- TronTransfer) {
+	watcher := newDepositWatcher(t, watched, time.Now())
+
+	ticker := time.NewTicker(depositPollInterval)
+	defer ticker.Stop()
+	for watcher.poll(ctx, deposits) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// depositFeed is one history listing of one watched address.
+type depositFeed struct {
+	addr string
+	kind string // "TRX" or "TRC-20"
+}
+
+// depositWatcher keeps a cursor per feed, so a feed that is behind or
+// failed to load is not skipped past by the others.
+type depositWatcher struct {
+	grid    *TronGridClient
+	watched []string
+	start   time.Time
+	cursors map[depositFeed]time.Time
+	seen    map[string]time.Time
+}
+
+func newDepositWatcher(t *TronGridClient, watched []string, start time.Time) *depositWatcher {
+	return &depositWatcher{
+		grid:    t,
+		watched: watched,
+		start:   start,
+		cursors: make(map[depositFeed]time.Time),
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// poll sends the new deposits of every feed and reports false once ctx is
+// done.
+func (w *depositWatcher) poll(ctx context.Context, deposits chan<- TronTransfer) bool {
+	lists := map[string]func(string, HistoryQuery) ([]TronTransfer, string, error){
+		"TRX":    w.grid.ListTRXTransfers,
+		"TRC-20": w.grid.ListTRC20Transfers,
+	}
+	for _, addr := range w.watched {
+		for _, kind := range []string{"TRX", "TRC-20"} {
+			feed := depositFeed{addr, kind}
+			cursor, ok := w.cursors[feed]
+			if !ok {
+				cursor = w.start
+			}
+			transfers, err := listAllTransfers(lists[kind], addr, HistoryQuery{Limit: 200, OnlyIncoming: true, Since: cursor.Add(-depositOverlap)})
+			if err != nil {
+				log.Printf("❌ Failed to poll %s deposits of %s: %v", kind, addr, err)
+				continue
+			}
+
+			for _, transfer := range transfers {
+				if transfer.Timestamp.After(cursor) {
+					cursor = transfer.Timestamp
+				}
+				key := transfer.TxID + "/" + transfer.To + "/" + transfer.Token
+				if _, ok := w.seen[key]; ok || transfer.To != addr || !transfer.Success || transfer.Timestamp.Before(w.start) {
+					continue
+				}
+				w.seen[key] = transfer.Timestamp
+				select {
+				case deposits <- transfer:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			w.cursors[feed] = cursor
+		}
+	}
+
+	// Forget transfers no feed can return again
+	var oldest time.Time
+	for _, addr := range w.watched {
+		for kind := range lists {
+			cursor, ok := w.cursors[depositFeed{addr, kind}]
+			if !ok {
+				cursor = w.start
+			}
+			if oldest.IsZero() || cursor.Before(oldest) {
+				oldest = cursor
+			}
+		}
+	}
+	for key, timestamp := range w.seen {
+		if timestamp.Before(oldest.Add(-depositOverlap)) {
+			delete(w.seen, key)
+		}
+	}
+	return ctx.Err() == nil
+}
+
+// listAllTransfers follows fingerprints until every page is fetched.
+func listAllTransfers(list func(string, HistoryQuery) ([]TronTransfer, string, error), addr string, query HistoryQuery) ([]TronTransfer, error) {
+	var all []TronTransfer
+	for {
+		transfers, next, err := list(addr, query)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, transfers...)
+		if next == "" {
+			return all, nil
+		}
+		query.Fingerprint = next
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+)
+
+// indexedTransfer is a transfer the history stub returns once visible.
+type indexedTransfer struct {
+	trc20     bool
+	txID      string
+	to        string
+	timestamp time.Time
+	visible   bool
+}
+
+// historyStub serves the TronGrid v1 transfer listings, filtered by
+// min_timestamp like TronGrid.
+type historyStub struct {
+	t         *testing.T
+	transfers []*indexedTransfer
+}
+
+func (s *historyStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/accounts/")
+	addr, trc20 := strings.TrimSuffix(path, "/transactions/trc20"), strings.HasSuffix(path, "/trc20")
+	addr = strings.TrimSuffix(addr, "/transactions")
+	minTimestamp, _ := strconv.ParseInt(r.URL.Query().Get("min_timestamp"), 10, 64)
+	if r.URL.Query().Get("only_to") != "true" || r.URL.Query().Get("only_confirmed") != "true" {
+		s.t.Errorf("unexpected query %s", r.URL.RawQuery)
+	}
+
+	data := []interface{}{}
+	for _, transfer := range s.transfers {
+		if !transfer.visible || transfer.trc20 != trc20 || transfer.to != addr || transfer.timestamp.UnixMilli() < minTimestamp {
+			continue
+		}
+		if trc20 {
+			data = append(data, map[string]interface{}{
+				"transaction_id":  transfer.txID,
+				"block_timestamp": transfer.timestamp.UnixMilli(),
+				"from":            testReceiver,
+				"to":              transfer.to,
+				"type":            "Transfer",
+				"value":           "1000000",
+				"token_info":      map[string]interface{}{"address": "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", "symbol": "USDT", "decimals": 6},
+			})
+			continue
+		}
+		to, err := address.Base58ToAddress(transfer.to)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		data = append(data, map[string]interface{}{
+			"txID":            transfer.txID,
+			"block_timestamp": transfer.timestamp.UnixMilli(),
+			"raw_data": map[string]interface{}{"contract": []interface{}{map[string]interface{}{
+				"type":      "TransferContract",
+				"parameter": map[string]interface{}{"value": map[string]interface{}{"amount": 5000000, "owner_address": to.Hex(), "to_address": to.Hex()}},
+			}}},
+			"ret": []interface{}{map[string]string{"contractRet": "SUCCESS"}},
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "meta": map[string]interface{}{}})
+}
+
+func TestDepositWatcherPerFeedCursors(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+	late := &indexedTransfer{trc20: true, txID: "b1", to: testOwner, timestamp: start.Add(5 * time.Second)}
+	stub := &historyStub{t: t, transfers: []*indexedTransfer{
+		{txID: "old", to: testOwner, timestamp: start.Add(-30 * time.Second), visible: true},
+		{txID: "a1", to: testOwner, timestamp: start.Add(10 * time.Second), visible: true},
+		late,
+	}}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	watcher := newDepositWatcher(ConnectTronGrid(server.URL, ""), []string{testOwner}, start)
+	deposits := make(chan TronTransfer, 10)
+	poll := func() []string {
+		if !watcher.poll(context.Background(), deposits) {
+			t.Fatal("poll stopped")
+		}
+		var txIDs []string
+		for len(deposits) > 0 {
+			txIDs = append(txIDs, (<-deposits).TxID)
+		}
+		return txIDs
+	}
+	expect := func(round string, got []string, want ...string) {
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: got deposits %v, want %v", round, got, want)
+		}
+	}
+
+	expect("first poll", poll(), "a1")
+
+	// The TRC-20 transfer is older than the newest TRX one but indexed
+	// only now; its own feed cursor has not moved past it.
+	late.visible = true
+	stub.transfers = append(stub.transfers, &indexedTransfer{txID: "a2", to: testOwner, timestamp: start.Add(20 * time.Second), visible: true})
+	expect("second poll", poll(), "a2", "b1")
+
+	// A TRX transfer indexed late within the overlap window still shows
+	// up, and nothing is delivered twice.
+	stub.transfers = append(stub.transfers, &indexedTransfer{txID: "a3", to: testOwner, timestamp: start.Add(15 * time.Second), visible: true})
+	expect("third poll", poll(), "a3")
+	expect("fourth poll", poll())
+
+	if cursor := watcher.cursors[depositFeed{testOwner, "TRX"}]; !cursor.Equal(start.Add(20 * time.Second)) {
+		t.Errorf("TRX cursor at %s, want the newest TRX transfer", cursor.Sub(start))
+	}
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/fbsobreira/gotron-sdk/pkg/address"
 	"github.com/fbsobreira/gotron-sdk/pkg/client"
 	"github.com/fbsobreira/gotron-sdk/pkg/client/transaction"
-	// "github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
 	// "github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"github.com/mr-tron/base58"
 	"google.golang.org/grpc"
@@ -110,7 +110,7 @@ func LoadAccount(privateKeyHex string) (*ecdsa.PrivateKey, address.Address) {
 // -------------------------------
 // 💰 Get Account Balance
 // -------------------------------
-func GetBalance(c *client.GrpcClient, addr address.Address) *big.Float {
+func GetBalance(c TronBackend, addr address.Address) *big.Float {
 	account, err := c.GetAccount(addr.String())
 	if err != nil && err.Error() != "account not found" {
 		log.Fatalf("❌ Failed to get balance: %v", err)
@@ -128,7 +128,7 @@ func GetBalance(c *client.GrpcClient, addr address.Address) *big.Float {
 // -------------------------------
 // 🚀 Send Transaction
 // -------------------------------
-func SendTransaction(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, toAddr address.Address, amountTrx float64) {
+func SendTransaction(c TronBackend, privateKey *ecdsa.PrivateKey, toAddr address.Address, amountTrx float64) {
 	publicKey := privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
 	if !ok {
@@ -144,12 +144,23 @@ func SendTransaction(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, toAddr
 		log.Fatalf("❌ Failed to create transaction: %v", err)
 	}
 
-	signedTx, err := transaction.SignTransactionECDSA(txExt.Transaction, privateKey)
-	if err != nil {
+	signAndBroadcast(c, privateKey, txExt)
+}
+
+func signAndBroadcast(c TronBackend, privateKey *ecdsa.PrivateKey, txExt *api.TransactionExtention) string {
+	// Some builders return the node's rejection in Result instead of an error
+	if txExt.GetResult().GetCode() != 0 || txExt.Transaction == nil {
+		log.Fatalf("❌ Failed to create transaction: %s", txExt.GetResult().GetMessage())
+	}
+
+	if _, err := transaction.SignTransactionECDSA(txExt.Transaction, privateKey); err != nil {
 		log.Fatalf("❌ Failed to sign transaction: %v", err)
 	}
+	return broadcastTransaction(c, txExt)
+}
 
-	result, err := c.Broadcast(signedTx)
+func broadcastTransaction(c TronBackend, txExt *api.TransactionExtention) string {
+	result, err := c.Broadcast(txExt.Transaction)
 	if err != nil {
 		log.Fatalf("❌ Failed to send transaction: %v", err)
 	}
@@ -157,7 +168,9 @@ func SendTransaction(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, toAddr
 		log.Fatalf("❌ Transaction failed: %s", result.Message)
 	}
 
-	fmt.Printf("✅ Transaction sent successfully!\n🔗 Hash: %s\n", hex.EncodeToString(txExt.Txid))
+	txID := hex.EncodeToString(txExt.Txid)
+	fmt.Printf("✅ Transaction sent successfully!\n🔗 Hash: %s\n", txID)
+	return txID
 }
 
 // Helper function to derive Tron address
@@ -234,4 +247,60 @@ func main() {
 		balance := GetBalance(c, addr)
 		fmt.Printf("%s: %f TRX\n", name, balance)
 	}
+
+	// 4️⃣ Check a USDT balance and send TRC-20 tokens (uncomment to test)
+	// c := ConnectClient(mainnets["Tron Mainnet"])
+	// usdt := GetTRC20Token(c, USDTContract)
+	// fmt.Printf("USDT: %s %s\n", GetTRC20Balance(c, addr, usdt).Text('f', usdt.Decimals), usdt.Symbol)
+	// privateKey, _ := LoadAccount(privateKeyHex)
+	// toAddr, _ := address.Base58ToAddress("T...")
+	// SendTRC20(c, privateKey, toAddr, usdt, 1.5, DefaultFeeLimit)
+
+	// 5️⃣ Stake TRX for energy and delegate it (uncomment to test)
+	// PrintAccountResources(GetAccountResources(c, addr))
+	// FreezeTRX(c, privateKey, 100, Energy)
+	// DelegateResource(c, privateKey, toAddr, 50, Energy, 0)
+	// UndelegateResource(c, privateKey, toAddr, 50, Energy)
+	// UnfreezeTRX(c, privateKey, 100, Energy)
+	// WithdrawExpiredUnfreeze(c, privateKey)
+
+	// 6️⃣ Use TronGrid's HTTP API (TLS + API key) where gRPC is blocked (uncomment to test)
+	// grid := ConnectTronGrid("https://api.trongrid.io", "YOUR_TRONGRID_API_KEY") // Nile: https://nile.trongrid.io
+	// fmt.Printf("TronGrid: %f TRX\n", GetBalance(grid, addr))
+	// SendTransaction(grid, privateKey, toAddr, 1)
+
+	// 7️⃣ Send from a 2-of-3 multisig account via its first active permission (uncomment to test)
+	// owner, actives := GetPermissions(c, addr)
+	// PrintPermissions(owner, actives)
+	// key2, _ := LoadAccount("SECOND_PRIVATE_KEY_HEX")
+	// txExt := BuildMultisigTransfer(c, addr, toAddr, 1, FirstActivePermissionID)
+	// SignMultisig(txExt, privateKey, key2)
+	// BroadcastMultisig(c, txExt)
+
+	// 8️⃣ Inspect a sent transaction, list history and watch for deposits (uncomment to test)
+	// txID := SendTRC20(c, privateKey, toAddr, usdt, 1, DefaultFeeLimit)
+	// PrintTransactionInfo(WaitForTransactionInfo(c, txID))
+	// transfers, next, _ := grid.ListTRC20Transfers(addr.String(), HistoryQuery{Limit: 20, Contract: USDTContract})
+	// for _, transfer := range transfers {
+	// 	PrintTransfer(addr.String(), transfer)
+	// }
+	// fmt.Println("Next page:", next)
+	// deposits := make(chan TronTransfer)
+	// go WatchDeposits(context.Background(), grid, []string{addr.String()}, deposits)
+	// for deposit := range deposits {
+	// 	PrintTransfer(addr.String(), deposit)
+	// }
+
+	// 9️⃣ Deploy a contract and call its methods (uncomment to test)
+	// abiJSON, _ := os.ReadFile("Token.abi")
+	// bytecode, _ := os.ReadFile("Token.bin")
+	// txID, tokenAddr := DeployContract(c, privateKey, "Token", string(abiJSON), string(bytecode), DeployOptions{
+	// 	UserResourcePercent: 100,
+	// 	ConstructorParams:   `[{"uint256":"1000000"}]`,
+	// })
+	// PrintTransactionInfo(WaitForTransactionInfo(c, txID))
+	// values := CallContract(c, tokenAddr.String(), "balanceOf(address)", `[{"address":"`+addr.String()+`"}]`, nil)
+	// fmt.Println("Balance:", FormatContractValue(values[0]))
+	// TriggerContract(c, privateKey, tokenAddr.String(), "transfer(address,uint256)",
+	// 	`[{"address":"`+toAddr.String()+`"},{"uint256":"100"}]`, 0, DefaultFeeLimit)
 }
\ No newline at end of file
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/client/transaction"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+)
+
+// Permission IDs: the owner permission is always 0 and active permissions
+// are numbered from 2.
+const (
+	OwnerPermissionID       = 0
+	FirstActivePermissionID = 2
+)
+
+// PermissionSpec describes an owner or active permission for
+// UpdatePermissions.
+type PermissionSpec struct {
+	Name      string
+	Threshold int64
+	// Keys maps base58 addresses to their weight (at most 5 keys).
+	Keys map[string]int64
+	// Operations lists the contract types an active permission may sign,
+	// e.g. "TransferContract". It is ignored for the owner permission.
+	Operations []string
+}
+
+// -------------------------------
+// 🔑 View Permissions
+// -------------------------------
+
+// GetPermissions returns the owner and active permissions of addr.
+func GetPermissions(c *client.GrpcClient, addr address.Address) (*core.Permission, []*core.Permission) {
+	account, err := c.GetAccount(addr.String())
+	if err != nil {
+		log.Fatalf("❌ Failed to get account: %v", err)
+	}
+	return account.OwnerPermission, account.ActivePermission
+}
+
+// PrintPermissions prints thresholds, weighted keys and allowed operations.
+func PrintPermissions(owner *core.Permission, actives []*core.Permission) {
+	for _, permission := range append([]*core.Permission{owner}, actives...) {
+		if permission == nil {
+			continue
+		}
+		fmt.Printf("🔑 [%d] %s (%s): threshold %d\n", permission.Id, permission.PermissionName, permission.Type, permission.Threshold)
+		for _, key := range permission.Keys {
+			fmt.Printf("   %s weight %d\n", address.Address(key.Address).String(), key.Weight)
+		}
+		if permission.Type == core.Permission_Active {
+			fmt.Printf("   operations: %s\n", strings.Join(permissionOperations(permission.Operations), ", "))
+		}
+	}
+}
+
+// permissionOperations decodes an operations bitmap, where bit n (LSB
+// first within each byte) allows contract type n.
+func permissionOperations(bitmap []byte) []string {
+	var operations []string
+	for value, name := range core.Transaction_Contract_ContractType_name {
+		if int(value/8) < len(bitmap) && bitmap[value/8]&(1<<(value%8)) != 0 {
+			operations = append(operations, name)
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// -------------------------------
+// 🛠️ Update Permissions
+// -------------------------------
+
+// UpdatePermissions replaces the owner and active permissions of the
+// account. The node charges a fee (100 TRX on mainnet) for this and a
+// wrong key set can lock the account, so double-check the specs.
+func UpdatePermissions(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, owner PermissionSpec, actives []PermissionSpec) string {
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+
+	ownerSpec := map[string]interface{}{"threshold": owner.Threshold, "keys": owner.Keys}
+	var activeSpecs []map[string]interface{}
+	for _, active := range actives {
+		operations := make(map[string]bool)
+		for _, operation := range active.Operations {
+			operations[operation] = true
+		}
+		activeSpecs = append(activeSpecs, map[string]interface{}{
+			"name":       active.Name,
+			"threshold":  active.Threshold,
+			"operations": operations,
+			"keys":       active.Keys,
+		})
+	}
+
+	txExt, err := c.UpdateAccountPermission(fromAddr.String(), ownerSpec, nil, activeSpecs)
+	if err != nil {
+		log.Fatalf("❌ Failed to create permission update: %v", err)
+	}
+	fmt.Printf("🛠️ Updating permissions of %s\n", fromAddr.String())
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// -------------------------------
+// ✍️ Multisig Transactions
+// -------------------------------
+
+// BuildMultisigTransfer creates an unsigned TRX transfer from fromAddr to
+// be signed under permissionID.
+func BuildMultisigTransfer(c *client.GrpcClient, fromAddr, toAddr address.Address, amountTrx float64, permissionID int32) *api.TransactionExtention {
+	txExt, err := c.Transfer(fromAddr.String(), toAddr.String(), TrxToSun(amountTrx))
+	if err != nil {
+		log.Fatalf("❌ Failed to create transaction: %v", err)
+	}
+	setPermissionID(txExt, permissionID)
+	return txExt
+}
+
+// setPermissionID selects the permission a transaction is signed under.
+// Changing it changes the raw data, so the txid is recomputed.
+func setPermissionID(txExt *api.TransactionExtention, permissionID int32) {
+	for _, contract := range txExt.Transaction.RawData.Contract {
+		contract.PermissionId = permissionID
+	}
+	if err := txExt.UpdateHash(); err != nil {
+		log.Fatalf("❌ Failed to update transaction hash: %v", err)
+	}
+}
+
+// SignMultisig adds a signature from every key, e.g. keys loaded via
+// LoadAccount.
+func SignMultisig(txExt *api.TransactionExtention, privateKeys ...*ecdsa.PrivateKey) {
+	for _, privateKey := range privateKeys {
+		if _, err := transaction.SignTransactionECDSA(txExt.Transaction, privateKey); err != nil {
+			log.Fatalf("❌ Failed to sign transaction: %v", err)
+		}
+		fmt.Printf("✍️ Signed by %s\n", deriveTronAddress(&privateKey.PublicKey).String())
+	}
+}
+
+// CheckSignWeight asks the node for the weight the signatures collected so
+// far carry and whether it reaches the permission threshold.
+func CheckSignWeight(c *client.GrpcClient, txExt *api.TransactionExtention) bool {
+	weight, err := c.GetTransactionSignWeight(txExt.Transaction)
+	if err != nil {
+		log.Fatalf("❌ Failed to get signature weight: %v", err)
+	}
+	result := weight.GetResult()
+	enough := result.GetCode() == api.TransactionSignWeight_Result_ENOUGH_PERMISSION
+	if !enough && result.GetCode() != api.TransactionSignWeight_Result_NOT_ENOUGH_PERMISSION {
+		log.Fatalf("❌ Invalid signatures: %s %s", result.GetCode(), result.GetMessage())
+	}
+
+	fmt.Printf("⚖️ Weight %d/%d for permission %q, approved by:\n",
+		weight.CurrentWeight, weight.GetPermission().GetThreshold(), weight.GetPermission().GetPermissionName())
+	for _, approver := range weight.ApprovedList {
+		fmt.Printf("   %s\n", address.Address(approver).String())
+	}
+	return enough
+}
+
+// BroadcastMultisig broadcasts a transaction once its signatures reach the
+// permission threshold.
+func BroadcastMultisig(c *client.GrpcClient, txExt *api.TransactionExtention) string {
+	if !CheckSignWeight(c, txExt) {
+		log.Fatal("❌ Signature weight is below the permission threshold, not broadcasting")
+	}
+	return broadcastTransaction(c, txExt)
+}
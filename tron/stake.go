@@ -0,0 +1,215 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+)
+
+// Stake 2.0 resource types.
+const (
+	Bandwidth = core.ResourceCode_BANDWIDTH
+	Energy    = core.ResourceCode_ENERGY
+)
+
+// AccountResources is the bandwidth and energy of an account together
+// with its Stake 2.0 positions. TRX amounts are in sun.
+type AccountResources struct {
+	FreeBandwidthUsed  int64
+	FreeBandwidthLimit int64
+	BandwidthUsed      int64
+	BandwidthLimit     int64
+	EnergyUsed         int64
+	EnergyLimit        int64
+	Staked             map[core.ResourceCode]int64
+	Unstaking          []Unstake
+	Delegations        []Delegation
+}
+
+// Unstake is TRX unfrozen under Stake 2.0 that can be withdrawn after
+// ExpireTime.
+type Unstake struct {
+	Resource   core.ResourceCode
+	Amount     int64
+	ExpireTime time.Time
+}
+
+// Delegation is staked TRX whose resources were delegated to Receiver.
+type Delegation struct {
+	Receiver  address.Address
+	Bandwidth int64
+	Energy    int64
+}
+
+// -------------------------------
+// 📊 Account Resources
+// -------------------------------
+
+// GetAccountResources returns the resources and stakes of addr.
+func GetAccountResources(c *client.GrpcClient, addr address.Address) AccountResources {
+	resources, err := c.GetAccountResource(addr.String())
+	if err != nil {
+		log.Fatalf("❌ Failed to get account resources: %v", err)
+	}
+	result := AccountResources{
+		FreeBandwidthUsed:  resources.FreeNetUsed,
+		FreeBandwidthLimit: resources.FreeNetLimit,
+		BandwidthUsed:      resources.NetUsed,
+		BandwidthLimit:     resources.NetLimit,
+		EnergyUsed:         resources.EnergyUsed,
+		EnergyLimit:        resources.EnergyLimit,
+		Staked:             make(map[core.ResourceCode]int64),
+	}
+
+	account, err := c.GetAccount(addr.String())
+	if err != nil {
+		if err.Error() == "account not found" {
+			return result
+		}
+		log.Fatalf("❌ Failed to get account: %v", err)
+	}
+	for _, frozen := range account.FrozenV2 {
+		result.Staked[frozen.Type] += frozen.Amount
+	}
+	for _, unfrozen := range account.UnfrozenV2 {
+		result.Unstaking = append(result.Unstaking, Unstake{
+			Resource:   unfrozen.Type,
+			Amount:     unfrozen.UnfreezeAmount,
+			ExpireTime: time.UnixMilli(unfrozen.UnfreezeExpireTime),
+		})
+	}
+
+	delegated, err := c.GetDelegatedResourcesV2(addr.String())
+	if err != nil {
+		log.Fatalf("❌ Failed to get delegated resources: %v", err)
+	}
+	for _, list := range delegated {
+		for _, resource := range list.DelegatedResource {
+			result.Delegations = append(result.Delegations, Delegation{
+				Receiver:  address.Address(resource.To),
+				Bandwidth: resource.FrozenBalanceForBandwidth,
+				Energy:    resource.FrozenBalanceForEnergy,
+			})
+		}
+	}
+	return result
+}
+
+// PrintAccountResources prints resources as returned by GetAccountResources.
+func PrintAccountResources(resources AccountResources) {
+	fmt.Printf("📶 Bandwidth: %d/%d free, %d/%d staked\n",
+		resources.FreeBandwidthUsed, resources.FreeBandwidthLimit, resources.BandwidthUsed, resources.BandwidthLimit)
+	fmt.Printf("⚡ Energy: %d/%d used\n", resources.EnergyUsed, resources.EnergyLimit)
+	fmt.Printf("🔒 Staked: %f TRX for bandwidth, %f TRX for energy\n",
+		SunToTrx(resources.Staked[Bandwidth]), SunToTrx(resources.Staked[Energy]))
+	for _, unstake := range resources.Unstaking {
+		status := "withdrawable"
+		if time.Now().Before(unstake.ExpireTime) {
+			status = "until " + unstake.ExpireTime.Format(time.RFC3339)
+		}
+		fmt.Printf("⏳ Unstaking %f TRX (%s): %s\n", SunToTrx(unstake.Amount), unstake.Resource, status)
+	}
+	for _, delegation := range resources.Delegations {
+		fmt.Printf("🤝 Delegated to %s: %f TRX bandwidth, %f TRX energy\n",
+			delegation.Receiver.String(), SunToTrx(delegation.Bandwidth), SunToTrx(delegation.Energy))
+	}
+}
+
+// -------------------------------
+// 🔒 Stake and Unstake TRX
+// -------------------------------
+
+// FreezeTRX stakes amountTrx for bandwidth or energy under Stake 2.0.
+func FreezeTRX(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, amountTrx float64, resource core.ResourceCode) string {
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	txExt, err := c.FreezeBalanceV2(fromAddr.String(), resource, TrxToSun(amountTrx))
+	if err != nil {
+		log.Fatalf("❌ Failed to create freeze transaction: %v", err)
+	}
+	fmt.Printf("🔒 Staking %f TRX for %s\n", amountTrx, resource)
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// UnfreezeTRX unstakes amountTrx. The TRX can be withdrawn with
+// WithdrawExpiredUnfreeze once the unstaking period has passed.
+func UnfreezeTRX(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, amountTrx float64, resource core.ResourceCode) string {
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	count, err := c.GetAvailableUnfreezeCount(fromAddr.String())
+	if err != nil {
+		log.Fatalf("❌ Failed to get available unfreeze count: %v", err)
+	}
+	if count.Count == 0 {
+		log.Fatal("❌ Too many pending unstakes, withdraw expired ones first")
+	}
+
+	txExt, err := c.UnfreezeBalanceV2(fromAddr.String(), resource, TrxToSun(amountTrx))
+	if err != nil {
+		log.Fatalf("❌ Failed to create unfreeze transaction: %v", err)
+	}
+	fmt.Printf("🔓 Unstaking %f TRX from %s\n", amountTrx, resource)
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// WithdrawExpiredUnfreeze moves unstaked TRX whose waiting period ended
+// back to the balance. It returns "" if there is nothing to withdraw.
+func WithdrawExpiredUnfreeze(c *client.GrpcClient, privateKey *ecdsa.PrivateKey) string {
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	withdrawable, err := c.GetCanWithdrawUnfreezeAmount(fromAddr.String(), time.Now().UnixMilli())
+	if err != nil {
+		log.Fatalf("❌ Failed to get withdrawable amount: %v", err)
+	}
+	if withdrawable.Amount == 0 {
+		fmt.Println("✅ Nothing to withdraw")
+		return ""
+	}
+
+	txExt, err := c.WithdrawExpireUnfreeze(fromAddr.String(), time.Now().UnixMilli())
+	if err != nil {
+		log.Fatalf("❌ Failed to create withdraw transaction: %v", err)
+	}
+	fmt.Printf("💸 Withdrawing %f TRX of expired unstakes\n", SunToTrx(withdrawable.Amount))
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// -------------------------------
+// 🤝 Delegate Resources
+// -------------------------------
+
+// DelegateResource delegates the resources of amountTrx staked TRX to
+// receiver. A non-zero lockPeriod (in blocks of 3 s) prevents undelegating
+// before it ends.
+func DelegateResource(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, receiver address.Address, amountTrx float64, resource core.ResourceCode, lockPeriod int64) string {
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	amountSun := TrxToSun(amountTrx)
+
+	maxSize, err := c.GetCanDelegatedMaxSize(fromAddr.String(), int32(resource))
+	if err != nil {
+		log.Fatalf("❌ Failed to get delegatable amount: %v", err)
+	}
+	if amountSun > maxSize.MaxSize {
+		log.Fatalf("❌ Only %f TRX staked for %s can be delegated", SunToTrx(maxSize.MaxSize), resource)
+	}
+
+	txExt, err := c.DelegateResource(fromAddr.String(), receiver.String(), resource, amountSun, lockPeriod > 0, lockPeriod)
+	if err != nil {
+		log.Fatalf("❌ Failed to create delegate transaction: %v", err)
+	}
+	fmt.Printf("🤝 Delegating %s of %f TRX to %s\n", resource, amountTrx, receiver.String())
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// UndelegateResource reclaims resources of amountTrx delegated to receiver.
+func UndelegateResource(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, receiver address.Address, amountTrx float64, resource core.ResourceCode) string {
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	txExt, err := c.UnDelegateResource(fromAddr.String(), receiver.String(), resource, TrxToSun(amountTrx))
+	if err != nil {
+		log.Fatalf("❌ Failed to create undelegate transaction: %v", err)
+	}
+	fmt.Printf("↩️ Undelegating %s of %f TRX from %s\n", resource, amountTrx, receiver.String())
+	return signAndBroadcast(c, privateKey, txExt)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+)
+
+// USDTContract is the USDT TRC-20 contract on Tron mainnet.
+const USDTContract = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+
+// DefaultFeeLimit caps the TRX (in sun) a contract call may burn for
+// energy: 100 TRX.
+const DefaultFeeLimit = 100_000_000
+
+// TRC20Token holds the metadata of a TRC-20 contract.
+type TRC20Token struct {
+	Contract string
+	Symbol   string
+	Decimals int
+}
+
+// EnergyEstimate is the energy a contract call needs and what the sender
+// would burn in TRX if its staked energy does not cover it.
+type EnergyEstimate struct {
+	Energy    int64 // energy required by the call
+	Available int64 // energy available from staking
+	Price     int64 // sun per energy unit
+	BurnSun   int64 // TRX burned for the uncovered energy, in sun
+}
+
+// -------------------------------
+// 🪙 Token Metadata and Balance
+// -------------------------------
+
+// GetTRC20Token reads symbol and decimals of a TRC-20 contract.
+func GetTRC20Token(c *client.GrpcClient, contract string) TRC20Token {
+	symbol, err := c.TRC20GetSymbol(contract)
+	if err != nil {
+		log.Fatalf("❌ Failed to get token symbol: %v", err)
+	}
+	decimals, err := c.TRC20GetDecimals(contract)
+	if err != nil {
+		log.Fatalf("❌ Failed to get token decimals: %v", err)
+	}
+	return TRC20Token{Contract: contract, Symbol: symbol, Decimals: int(decimals.Int64())}
+}
+
+// GetTRC20Balance returns the token balance of addr in whole tokens.
+func GetTRC20Balance(c *client.GrpcClient, addr address.Address, token TRC20Token) *big.Float {
+	balance, err := c.TRC20ContractBalance(addr.String(), token.Contract)
+	if err != nil {
+		log.Fatalf("❌ Failed to get %s balance: %v", token.Symbol, err)
+	}
+	return BaseUnitsToToken(balance, token.Decimals)
+}
+
+// -------------------------------
+// ⚡ Estimate Energy
+// -------------------------------
+
+// EstimateTRC20Transfer returns the energy a transfer needs and the TRX it
+// would burn given the sender's available energy.
+func EstimateTRC20Transfer(c *client.GrpcClient, from, to address.Address, token TRC20Token, amount *big.Int) EnergyEstimate {
+	params := fmt.Sprintf(`[{"address":"%s"},{"uint256":"%s"}]`, to.String(), amount.String())
+	return estimateContractEnergy(c, from, token.Contract, "transfer(address,uint256)", params, 0)
+}
+
+// estimateContractEnergy estimates a call with wallet/estimateenergy and
+// falls back to a constant call's energy_used on nodes without it.
+func estimateContractEnergy(c *client.GrpcClient, from address.Address, contract, method, params string, callValue int64) EnergyEstimate {
+	var energy int64
+	if estimate, err := c.EstimateEnergy(from.String(), contract, method, params, callValue, "", 0); err == nil {
+		energy = estimate.EnergyRequired
+	} else {
+		result, err := c.TriggerConstantContract(from.String(), contract, method, params)
+		if err != nil {
+			log.Fatalf("❌ Failed to estimate energy: %v", err)
+		}
+		// A REVERT still reports result code SUCCESS; it shows in ret
+		if result.GetResult().GetCode() != 0 || isRevert(result) {
+			log.Fatalf("❌ Call would fail: %s", revertReason(result, constantResult(result)))
+		}
+		energy = result.EnergyUsed
+	}
+
+	resources, err := c.GetAccountResource(from.String())
+	if err != nil {
+		log.Fatalf("❌ Failed to get account resources: %v", err)
+	}
+	price := currentEnergyPrice(c)
+
+	estimate := EnergyEstimate{
+		Energy:    energy,
+		Available: max(resources.EnergyLimit-resources.EnergyUsed, 0),
+		Price:     price,
+	}
+	if uncovered := energy - estimate.Available; uncovered > 0 {
+		estimate.BurnSun = uncovered * price
+	}
+	return estimate
+}
+
+// currentEnergyPrice returns the current sun price of one energy unit.
+// The node reports the price history as "timestamp:price,..." with the
+// newest entry last.
+func currentEnergyPrice(c *client.GrpcClient) int64 {
+	prices, err := c.GetEnergyPrices()
+	if err != nil {
+		log.Fatalf("❌ Failed to get energy price: %v", err)
+	}
+	entries := strings.Split(prices.Prices, ",")
+	_, latest, _ := strings.Cut(entries[len(entries)-1], ":")
+	price, err := strconv.ParseInt(latest, 10, 64)
+	if err != nil {
+		log.Fatalf("❌ Invalid energy price %q: %v", prices.Prices, err)
+	}
+	return price
+}
+
+// -------------------------------
+// 🚀 Send TRC-20 Tokens
+// -------------------------------
+
+// SendTRC20 transfers amount whole tokens after checking that the energy
+// burn fits in feeLimit (sun; DefaultFeeLimit when 0).
+func SendTRC20(c *client.GrpcClient, privateKey *ecdsa.PrivateKey, toAddr address.Address, token TRC20Token, amount float64, feeLimit int64) string {
+	if feeLimit == 0 {
+		feeLimit = DefaultFeeLimit
+	}
+	fromAddr := deriveTronAddress(&privateKey.PublicKey)
+	amountUnits := TokenToBaseUnits(amount, token.Decimals)
+
+	balance, err := c.TRC20ContractBalance(fromAddr.String(), token.Contract)
+	if err != nil {
+		log.Fatalf("❌ Failed to get %s balance: %v", token.Symbol, err)
+	}
+	if balance.Cmp(amountUnits) < 0 {
+		log.Fatalf("❌ Insufficient funds: balance %s %s, need %s %s",
+			BaseUnitsToToken(balance, token.Decimals).Text('f', token.Decimals), token.Symbol,
+			BaseUnitsToToken(amountUnits, token.Decimals).Text('f', token.Decimals), token.Symbol)
+	}
+
+	estimate := EstimateTRC20Transfer(c, fromAddr, toAddr, token, amountUnits)
+	fmt.Printf("⚡ Energy: %d required, %d available, burns up to %f TRX\n", estimate.Energy, estimate.Available, SunToTrx(estimate.BurnSun))
+	if estimate.BurnSun > feeLimit {
+		log.Fatalf("❌ Energy burn of %f TRX exceeds fee limit of %f TRX", SunToTrx(estimate.BurnSun), SunToTrx(feeLimit))
+	}
+
+	txExt, err := c.TRC20Send(fromAddr.String(), toAddr.String(), token.Contract, amountUnits, feeLimit)
+	if err != nil {
+		log.Fatalf("❌ Failed to create transaction: %v", err)
+	}
+	return signAndBroadcast(c, privateKey, txExt)
+}
+
+// -------------------------------
+// ⚙️ Token Unit Conversions
+// -------------------------------
+
+// TokenToBaseUnits converts whole tokens to the contract's integer units.
+// The amount is formatted with exactly decimals digits first so values
+// like 0.1 convert without float rounding error.
+func TokenToBaseUnits(amount float64, decimals int) *big.Int {
+	text := strconv.FormatFloat(amount, 'f', decimals, 64)
+	units, ok := new(big.Int).SetString(strings.Replace(text, ".", "", 1), 10)
+	if !ok {
+		log.Fatalf("❌ Invalid token amount %v", amount)
+	}
+	return units
+}
+
+// BaseUnitsToToken converts contract units to whole tokens.
+func BaseUnitsToToken(units *big.Int, decimals int) *big.Float {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	return new(big.Float).Quo(new(big.Float).SetInt(units), scale)
+}